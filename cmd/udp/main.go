@@ -6,11 +6,9 @@ import (
 	"github.com/ascenmmo/udp-server/pkg/start"
 	"github.com/rs/zerolog"
 	"os"
-	"runtime"
 )
 
 func main() {
-	runtime.GOMAXPROCS(1)
 	logger := zerolog.New(os.Stdout).With().Timestamp().Logger()
 	ctx := context.Background()
 
@@ -1,9 +1,20 @@
 package env
 
+import "time"
+
 var (
 	ServerAddress       = "127.0.0.1"                        // Server address
 	TCPPort             = "8081"                             // Port for TCP connections
 	UDPPort             = "4500"                             // Port for UDP connections
+	UDPSockets          = 0                                  // Number of sockets sharing each UDP listen address through SO_REUSEPORT, 0 for one per CPU
+	UDPListenAddresses  = []string{}                         // UDP host:port endpoints to listen on, empty for UDPPort on every interface over IPv4 and IPv6
+	UDPEndpoints        = []string{}                         // UDP host:port endpoints returned to clients, empty to derive them from the listen addresses and ServerAddress
 	TokenKey            = "_remember_token_must_be_32_bytes" // Unique token for authentication
 	MaxRequestPerSecond = 200                                // Maximum number of requests per second
+	LegacyProtocol      = false                              // Accept raw-token clients that send datagrams without a protocol header
+	MTU                 = 1200                               // Maximum size of a datagram sent to clients, larger messages are fragmented
+	MaxFragmentedSize   = 256 * 1024                         // Maximum size of incomplete fragmented messages kept per session
+	PingInterval        = time.Second                        // How often the server pings each client to measure its link
+	IdleTimeout         = 10 * time.Second                   // Users that send nothing for this long are removed from their room
+	LegacyIdleTimeout   = 10 * time.Minute                   // IdleTimeout of raw-token clients, which are not pinged, 0 to keep them
 )
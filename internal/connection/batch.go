@@ -0,0 +1,150 @@
+package connection
+
+import (
+	"net"
+)
+
+const (
+	BatchSize = 64
+)
+
+// Message is one datagram of a batch. When reading, Buffer is filled and N
+// and Addr are set; when writing, Buffer is sent to Addr.
+type Message struct {
+	Buffer []byte
+	N      int
+	Addr   *net.UDPAddr
+}
+
+// BatchConn reads and writes datagrams in batches. On Linux a batch takes a
+// single recvmmsg or sendmmsg call; elsewhere, and when the socket does not
+// allow it, every datagram takes its own call.
+type BatchConn struct {
+	conn *net.UDPConn
+	io   batchIO
+}
+
+// ReadBatch blocks until at least one datagram arrives and reads up to
+// len(messages) of them.
+func (c *BatchConn) ReadBatch(messages []Message) (n int, err error) {
+	if len(messages) > BatchSize {
+		messages = messages[:BatchSize]
+	}
+	if !c.io.ok() {
+		return c.readOne(messages)
+	}
+	return c.io.readBatch(messages)
+}
+
+// WriteBatch writes up to BatchSize messages and returns how many were sent.
+// An error is returned only when the first message could not be sent.
+func (c *BatchConn) WriteBatch(messages []Message) (n int, err error) {
+	if len(messages) > BatchSize {
+		messages = messages[:BatchSize]
+	}
+	if !c.io.ok() {
+		return c.writeEach(messages)
+	}
+	return c.io.writeBatch(messages)
+}
+
+func (c *BatchConn) Conn() *net.UDPConn {
+	return c.conn
+}
+
+func (c *BatchConn) readOne(messages []Message) (n int, err error) {
+	read, addr, err := c.conn.ReadFromUDP(messages[0].Buffer)
+	if err != nil {
+		return 0, err
+	}
+	messages[0].N = read
+	messages[0].Addr = addr
+	return 1, nil
+}
+
+func (c *BatchConn) writeEach(messages []Message) (n int, err error) {
+	for i := range messages {
+		_, err = c.conn.WriteToUDP(messages[i].Buffer, messages[i].Addr)
+		if err != nil {
+			if i == 0 {
+				return 0, err
+			}
+			return i, nil
+		}
+	}
+	return len(messages), nil
+}
+
+func NewBatchConn(conn *net.UDPConn) *BatchConn {
+	return &BatchConn{
+		conn: conn,
+		io:   newBatchIO(conn),
+	}
+}
+
+// Batch collects the datagrams of one fan-out so that they leave each socket
+// together on Flush.
+type Batch struct {
+	queues []*batchQueue
+}
+
+// batchQueue holds the datagrams waiting to leave through one socket.
+type batchQueue struct {
+	conn     *BatchConn
+	messages []Message
+	senders  []DataSender
+}
+
+// Add queues a datagram for ds on the socket its connection is bound to.
+// Connections of other sockets are written immediately.
+func (b *Batch) Add(ds DataSender, buf []byte) error {
+	udp, ok := ds.(*UDPConnection)
+	if !ok {
+		return ds.Write(buf)
+	}
+
+	for _, q := range b.queues {
+		if udp.Conn == q.conn.conn {
+			q.messages = append(q.messages, Message{Buffer: buf, Addr: udp.ClientAddr})
+			q.senders = append(q.senders, ds)
+			return nil
+		}
+	}
+
+	return ds.Write(buf)
+}
+
+// Writer returns a write function that queues datagrams for ds.
+func (b *Batch) Writer(ds DataSender) func([]byte) error {
+	return func(buf []byte) error {
+		return b.Add(ds, buf)
+	}
+}
+
+// Flush sends the queued datagrams and returns the connections whose
+// datagrams could not be sent.
+func (b *Batch) Flush() (failed []DataSender) {
+	for _, q := range b.queues {
+		messages, senders := q.messages, q.senders
+		for len(messages) > 0 {
+			n, err := q.conn.WriteBatch(messages)
+			if err != nil {
+				failed = append(failed, senders[0])
+				n = 1
+			}
+			messages, senders = messages[n:], senders[n:]
+		}
+
+		q.messages, q.senders = q.messages[:0], q.senders[:0]
+	}
+
+	return failed
+}
+
+func NewBatch(conns ...*BatchConn) *Batch {
+	b := &Batch{queues: make([]*batchQueue, len(conns))}
+	for i, conn := range conns {
+		b.queues[i] = &batchQueue{conn: conn}
+	}
+	return b
+}
@@ -0,0 +1,215 @@
+//go:build linux
+
+package connection
+
+import (
+	"golang.org/x/sys/unix"
+	"net"
+	"runtime"
+	"strconv"
+	"sync"
+	"syscall"
+	"unsafe"
+)
+
+type mmsghdr struct {
+	hdr unix.Msghdr
+	len uint32
+}
+
+// scratch holds the kernel structures of one batch call.
+type scratch struct {
+	hdrs  [BatchSize]mmsghdr
+	iovs  [BatchSize]unix.Iovec
+	names [BatchSize]unix.RawSockaddrAny
+}
+
+var scratchPool = sync.Pool{
+	New: func() any {
+		return new(scratch)
+	},
+}
+
+// batchIO issues recvmmsg and sendmmsg on the socket through the runtime
+// poller, so a call waits for readiness without blocking an OS thread.
+type batchIO struct {
+	raw    syscall.RawConn
+	family int
+}
+
+func newBatchIO(conn *net.UDPConn) batchIO {
+	raw, err := conn.SyscallConn()
+	if err != nil {
+		return batchIO{}
+	}
+
+	var family int
+	var sockErr error
+	err = raw.Control(func(fd uintptr) {
+		family, sockErr = unix.GetsockoptInt(int(fd), unix.SOL_SOCKET, unix.SO_DOMAIN)
+	})
+	if err != nil || sockErr != nil {
+		return batchIO{}
+	}
+
+	return batchIO{raw: raw, family: family}
+}
+
+func (b batchIO) ok() bool {
+	return b.raw != nil
+}
+
+func (b batchIO) readBatch(messages []Message) (n int, err error) {
+	sc := scratchPool.Get().(*scratch)
+	defer scratchPool.Put(sc)
+
+	hdrs, iovs, names := sc.hdrs[:len(messages)], sc.iovs[:], sc.names[:]
+	for i := range messages {
+		iovs[i].Base = &messages[i].Buffer[0]
+		iovs[i].SetLen(len(messages[i].Buffer))
+		hdrs[i].hdr.Name = (*byte)(unsafe.Pointer(&names[i]))
+		hdrs[i].hdr.Namelen = uint32(unsafe.Sizeof(names[i]))
+		hdrs[i].hdr.Iov = &iovs[i]
+		hdrs[i].hdr.SetIovlen(1)
+	}
+
+	var errno syscall.Errno
+	err = b.raw.Read(func(fd uintptr) bool {
+		r, _, e := unix.Syscall6(unix.SYS_RECVMMSG, fd, uintptr(unsafe.Pointer(&hdrs[0])), uintptr(len(hdrs)), 0, 0, 0)
+		if e == unix.EAGAIN || e == unix.EINTR {
+			return false
+		}
+		n, errno = int(r), e
+		return true
+	})
+	runtime.KeepAlive(messages)
+	if err != nil {
+		return 0, err
+	}
+	if errno != 0 {
+		return 0, errno
+	}
+
+	for i := 0; i < n; i++ {
+		messages[i].N = int(hdrs[i].len)
+		messages[i].Addr = sockaddrToUDP(&names[i])
+	}
+
+	return n, nil
+}
+
+func (b batchIO) writeBatch(messages []Message) (n int, err error) {
+	sc := scratchPool.Get().(*scratch)
+	defer scratchPool.Put(sc)
+
+	hdrs, iovs, names := sc.hdrs[:len(messages)], sc.iovs[:], sc.names[:]
+	for i := range messages {
+		namelen, err := b.sockaddr(messages[i].Addr, &names[i])
+		if err != nil {
+			if i == 0 {
+				return 0, err
+			}
+			hdrs = hdrs[:i]
+			break
+		}
+		iovs[i].Base = nil
+		if len(messages[i].Buffer) > 0 {
+			iovs[i].Base = &messages[i].Buffer[0]
+		}
+		iovs[i].SetLen(len(messages[i].Buffer))
+		hdrs[i].hdr.Name = (*byte)(unsafe.Pointer(&names[i]))
+		hdrs[i].hdr.Namelen = namelen
+		hdrs[i].hdr.Iov = &iovs[i]
+		hdrs[i].hdr.SetIovlen(1)
+	}
+
+	var errno syscall.Errno
+	err = b.raw.Write(func(fd uintptr) bool {
+		r, _, e := unix.Syscall6(unix.SYS_SENDMMSG, fd, uintptr(unsafe.Pointer(&hdrs[0])), uintptr(len(hdrs)), 0, 0, 0)
+		if e == unix.EAGAIN || e == unix.EINTR {
+			return false
+		}
+		n, errno = int(r), e
+		return true
+	})
+	runtime.KeepAlive(messages)
+	if err != nil {
+		return 0, err
+	}
+	if errno != 0 {
+		return 0, errno
+	}
+
+	return n, nil
+}
+
+// sockaddr fills sa with addr in the socket's family. IPv4 addresses are
+// mapped into IPv6 on dual-stack sockets.
+func (b batchIO) sockaddr(addr *net.UDPAddr, sa *unix.RawSockaddrAny) (namelen uint32, err error) {
+	if b.family == unix.AF_INET {
+		ip := addr.IP.To4()
+		if ip == nil {
+			return 0, unix.EAFNOSUPPORT
+		}
+		sa4 := (*unix.RawSockaddrInet4)(unsafe.Pointer(sa))
+		sa4.Family = unix.AF_INET
+		putPort(&sa4.Port, addr.Port)
+		copy(sa4.Addr[:], ip)
+		return unix.SizeofSockaddrInet4, nil
+	}
+
+	ip := addr.IP.To16()
+	if ip == nil {
+		return 0, unix.EAFNOSUPPORT
+	}
+	sa6 := (*unix.RawSockaddrInet6)(unsafe.Pointer(sa))
+	sa6.Family = unix.AF_INET6
+	putPort(&sa6.Port, addr.Port)
+	copy(sa6.Addr[:], ip)
+	sa6.Scope_id = zoneToIndex(addr.Zone)
+	return unix.SizeofSockaddrInet6, nil
+}
+
+func sockaddrToUDP(sa *unix.RawSockaddrAny) *net.UDPAddr {
+	switch sa.Addr.Family {
+	case unix.AF_INET:
+		sa4 := (*unix.RawSockaddrInet4)(unsafe.Pointer(sa))
+		return &net.UDPAddr{IP: net.IPv4(sa4.Addr[0], sa4.Addr[1], sa4.Addr[2], sa4.Addr[3]), Port: getPort(&sa4.Port)}
+	case unix.AF_INET6:
+		sa6 := (*unix.RawSockaddrInet6)(unsafe.Pointer(sa))
+		addr := &net.UDPAddr{IP: append(net.IP(nil), sa6.Addr[:]...), Port: getPort(&sa6.Port)}
+		if sa6.Scope_id != 0 {
+			addr.Zone = indexToZone(sa6.Scope_id)
+		}
+		return addr
+	}
+	return &net.UDPAddr{}
+}
+
+func putPort(dst *uint16, port int) {
+	p := (*[2]byte)(unsafe.Pointer(dst))
+	p[0], p[1] = byte(port>>8), byte(port)
+}
+
+func getPort(src *uint16) int {
+	p := (*[2]byte)(unsafe.Pointer(src))
+	return int(p[0])<<8 | int(p[1])
+}
+
+func zoneToIndex(zone string) uint32 {
+	if zone == "" {
+		return 0
+	}
+	if iface, err := net.InterfaceByName(zone); err == nil {
+		return uint32(iface.Index)
+	}
+	index, _ := strconv.Atoi(zone)
+	return uint32(index)
+}
+
+func indexToZone(index uint32) string {
+	if iface, err := net.InterfaceByIndex(int(index)); err == nil {
+		return iface.Name
+	}
+	return strconv.Itoa(int(index))
+}
@@ -0,0 +1,23 @@
+//go:build !linux
+
+package connection
+
+import "net"
+
+type batchIO struct{}
+
+func newBatchIO(*net.UDPConn) batchIO {
+	return batchIO{}
+}
+
+func (batchIO) ok() bool {
+	return false
+}
+
+func (batchIO) readBatch([]Message) (int, error) {
+	panic("unreachable")
+}
+
+func (batchIO) writeBatch([]Message) (int, error) {
+	panic("unreachable")
+}
@@ -0,0 +1,219 @@
+package connection
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"net"
+	"testing"
+	"time"
+)
+
+func listen(t testing.TB, network, address string) *net.UDPConn {
+	addr, err := net.ResolveUDPAddr(network, address)
+	assert.NoError(t, err)
+	conn, err := net.ListenUDP(network, addr)
+	assert.NoError(t, err)
+	return conn
+}
+
+func readAll(t testing.TB, c *BatchConn, count int) (received []Message) {
+	assert.NoError(t, c.conn.SetReadDeadline(time.Now().Add(time.Second*2)))
+	for len(received) < count {
+		messages := make([]Message, BatchSize)
+		for i := range messages {
+			messages[i].Buffer = make([]byte, 1500)
+		}
+		n, err := c.ReadBatch(messages)
+		if !assert.NoError(t, err) {
+			return received
+		}
+		received = append(received, messages[:n]...)
+	}
+	return received
+}
+
+func TestBatchRoundTrip(t *testing.T) {
+	for _, tc := range []struct{ network, address string }{
+		{"udp4", "127.0.0.1:0"},
+		{"udp", ":0"},
+	} {
+		t.Run(tc.network, func(t *testing.T) {
+			server := NewBatchConn(listen(t, tc.network, tc.address))
+			defer server.conn.Close()
+			client := NewBatchConn(listen(t, "udp4", "127.0.0.1:0"))
+			defer client.conn.Close()
+
+			serverAddr := &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: server.conn.LocalAddr().(*net.UDPAddr).Port}
+			messages := make([]Message, 10)
+			for i := range messages {
+				messages[i] = Message{Buffer: bytes.Repeat([]byte{byte(i)}, 10+i), Addr: serverAddr}
+			}
+			n, err := client.WriteBatch(messages)
+			assert.NoError(t, err)
+			assert.Equal(t, len(messages), n)
+
+			received := readAll(t, server, len(messages))
+			assert.Len(t, received, len(messages))
+			for i, msg := range received {
+				assert.Equal(t, messages[i].Buffer, msg.Buffer[:msg.N])
+				assert.Equal(t, client.conn.LocalAddr().(*net.UDPAddr).Port, msg.Addr.Port)
+				assert.Equal(t, "127.0.0.1", msg.Addr.IP.String())
+			}
+
+			// Reply through a fan-out batch to the addresses just read.
+			batch := NewBatch(server)
+			for i, msg := range received {
+				ds := &UDPConnection{ClientAddr: msg.Addr, Conn: server.conn}
+				assert.NoError(t, batch.Add(ds, []byte{byte(i)}))
+			}
+			assert.Empty(t, batch.Flush())
+
+			replies := readAll(t, client, len(received))
+			assert.Len(t, replies, len(received))
+		})
+	}
+}
+
+func TestBatchReportsFailedSenders(t *testing.T) {
+	server := NewBatchConn(listen(t, "udp4", "127.0.0.1:0"))
+	defer server.conn.Close()
+
+	bad := &UDPConnection{ClientAddr: &net.UDPAddr{IP: net.ParseIP("::1"), Port: 9}, Conn: server.conn}
+	good := &UDPConnection{ClientAddr: &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 9}, Conn: server.conn}
+
+	batch := NewBatch(server)
+	assert.NoError(t, batch.Add(good, []byte("a")))
+	assert.NoError(t, batch.Add(bad, []byte("b")))
+	assert.NoError(t, batch.Add(good, []byte("c")))
+
+	failed := batch.Flush()
+	assert.Equal(t, []DataSender{bad}, failed)
+}
+
+func TestBatchSendsFromBoundSocket(t *testing.T) {
+	first := NewBatchConn(listen(t, "udp4", "127.0.0.1:0"))
+	defer first.conn.Close()
+	second := NewBatchConn(listen(t, "udp4", "127.0.0.1:0"))
+	defer second.conn.Close()
+	client := NewBatchConn(listen(t, "udp4", "127.0.0.1:0"))
+	defer client.conn.Close()
+
+	clientAddr := client.conn.LocalAddr().(*net.UDPAddr)
+	batch := NewBatch(first, second)
+	assert.NoError(t, batch.Add(&UDPConnection{ClientAddr: clientAddr, Conn: second.conn}, []byte{2}))
+	assert.NoError(t, batch.Add(&UDPConnection{ClientAddr: clientAddr, Conn: first.conn}, []byte{1}))
+	assert.Empty(t, batch.Flush())
+
+	for _, msg := range readAll(t, client, 2) {
+		from := first
+		if msg.Buffer[0] == 2 {
+			from = second
+		}
+		assert.Equal(t, from.conn.LocalAddr().(*net.UDPAddr).Port, msg.Addr.Port)
+	}
+}
+
+const fanOut = 32
+
+func benchmarkFanOut(b *testing.B, send func(sender *BatchConn, messages []Message)) {
+	receiver := listen(b, "udp4", "127.0.0.1:0")
+	defer receiver.Close()
+	assert.NoError(b, receiver.SetReadBuffer(8<<20))
+	go func() {
+		buf := make([]byte, 1500)
+		for {
+			if _, _, err := receiver.ReadFromUDP(buf); err != nil {
+				return
+			}
+		}
+	}()
+
+	sender := NewBatchConn(listen(b, "udp4", "127.0.0.1:0"))
+	defer sender.conn.Close()
+
+	messages := make([]Message, fanOut)
+	for i := range messages {
+		messages[i] = Message{Buffer: make([]byte, 200), Addr: receiver.LocalAddr().(*net.UDPAddr)}
+	}
+
+	b.ReportAllocs()
+	b.SetBytes(int64(fanOut * 200))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		send(sender, messages)
+	}
+}
+
+// BenchmarkFanOutLoop is the previous write path: one WriteToUDP per
+// recipient.
+func BenchmarkFanOutLoop(b *testing.B) {
+	benchmarkFanOut(b, func(sender *BatchConn, messages []Message) {
+		for _, msg := range messages {
+			if _, err := sender.conn.WriteToUDP(msg.Buffer, msg.Addr); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkFanOutBatch(b *testing.B) {
+	benchmarkFanOut(b, func(sender *BatchConn, messages []Message) {
+		for len(messages) > 0 {
+			n, err := sender.WriteBatch(messages)
+			if err != nil {
+				b.Fatal(err)
+			}
+			messages = messages[n:]
+		}
+	})
+}
+
+func benchmarkRead(b *testing.B, read func(receiver *BatchConn, messages []Message) int) {
+	receiver := NewBatchConn(listen(b, "udp4", "127.0.0.1:0"))
+	defer receiver.conn.Close()
+	assert.NoError(b, receiver.conn.SetReadBuffer(8<<20))
+
+	sender := NewBatchConn(listen(b, "udp4", "127.0.0.1:0"))
+	defer sender.conn.Close()
+
+	out := make([]Message, fanOut)
+	in := make([]Message, BatchSize)
+	for i := range out {
+		out[i] = Message{Buffer: make([]byte, 200), Addr: receiver.conn.LocalAddr().(*net.UDPAddr)}
+	}
+	for i := range in {
+		in[i].Buffer = make([]byte, 1500)
+	}
+
+	b.ReportAllocs()
+	b.SetBytes(int64(fanOut * 200))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := sender.WriteBatch(out); err != nil {
+			b.Fatal(err)
+		}
+		for received := 0; received < fanOut; {
+			received += read(receiver, in)
+		}
+	}
+}
+
+// BenchmarkReadLoop is the previous read path: one ReadFromUDP per datagram.
+func BenchmarkReadLoop(b *testing.B) {
+	benchmarkRead(b, func(receiver *BatchConn, messages []Message) int {
+		if _, _, err := receiver.conn.ReadFromUDP(messages[0].Buffer); err != nil {
+			b.Fatal(err)
+		}
+		return 1
+	})
+}
+
+func BenchmarkReadBatch(b *testing.B) {
+	benchmarkRead(b, func(receiver *BatchConn, messages []Message) int {
+		n, err := receiver.ReadBatch(messages)
+		if err != nil {
+			b.Fatal(err)
+		}
+		return n
+	})
+}
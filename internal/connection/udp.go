@@ -2,13 +2,20 @@ package connection
 
 import "net"
 
+// UDPConnection is a client seen through one of the server sockets. Local is
+// the address of that socket; connections of the same client on different
+// listen addresses have different IDs.
 type UDPConnection struct {
 	ClientAddr *net.UDPAddr
 	Conn       *net.UDPConn
+	Local      string
 }
 
 func (u *UDPConnection) GetID() string {
 	add := u.ClientAddr.String()
+	if u.Local != "" {
+		add += "/" + u.Local
+	}
 	return add
 }
 
@@ -0,0 +1,62 @@
+package cookie
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"github.com/ascenmmo/udp-server/pkg/protocol"
+	"time"
+)
+
+const (
+	Window = time.Second * 10
+)
+
+// Generator issues stateless handshake cookies. A cookie binds the client
+// address to a time window, so echoing it back proves that the client owns
+// its source address. Cookies from the current and the previous window are
+// accepted.
+type Generator struct {
+	secret []byte
+}
+
+func (g *Generator) New(addr string) []byte {
+	return g.generate(addr, window(time.Now()))
+}
+
+func (g *Generator) Verify(addr string, cookie []byte) bool {
+	if len(cookie) != protocol.CookieSize {
+		return false
+	}
+
+	current := window(time.Now())
+	issued := binary.BigEndian.Uint32(cookie[:4])
+	if issued != current && issued+1 != current {
+		return false
+	}
+
+	return hmac.Equal(cookie, g.generate(addr, issued))
+}
+
+func (g *Generator) generate(addr string, window uint32) []byte {
+	cookie := binary.BigEndian.AppendUint32(make([]byte, 0, protocol.CookieSize), window)
+
+	mac := hmac.New(sha256.New, g.secret)
+	mac.Write(cookie)
+	mac.Write([]byte(addr))
+
+	return append(cookie, mac.Sum(nil)[:protocol.CookieSize-4]...)
+}
+
+func window(now time.Time) uint32 {
+	return uint32(now.Unix() / int64(Window/time.Second))
+}
+
+func NewGenerator() (*Generator, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+	return &Generator{secret: secret}, nil
+}
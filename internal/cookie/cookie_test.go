@@ -0,0 +1,27 @@
+package cookie
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestCookie(t *testing.T) {
+	g, err := NewGenerator()
+	assert.NoError(t, err)
+
+	cookie := g.New("127.0.0.1:5000")
+	assert.True(t, g.Verify("127.0.0.1:5000", cookie))
+	assert.False(t, g.Verify("127.0.0.1:5001", cookie))
+	assert.False(t, g.Verify("127.0.0.1:5000", cookie[:10]))
+
+	previous := g.generate("127.0.0.1:5000", window(time.Now().Add(-Window)))
+	assert.True(t, g.Verify("127.0.0.1:5000", previous))
+
+	expired := g.generate("127.0.0.1:5000", window(time.Now().Add(-Window*2)))
+	assert.False(t, g.Verify("127.0.0.1:5000", expired))
+
+	other, err := NewGenerator()
+	assert.NoError(t, err)
+	assert.False(t, other.Verify("127.0.0.1:5000", cookie))
+}
@@ -0,0 +1,97 @@
+package fragment
+
+import (
+	"github.com/ascenmmo/udp-server/pkg/errors"
+	"github.com/ascenmmo/udp-server/pkg/protocol"
+	"sync"
+	"time"
+)
+
+const (
+	Timeout = time.Second * 5
+)
+
+type group struct {
+	parts     [][]byte
+	arrived   []bool
+	received  int
+	size      int
+	createdAt time.Time
+}
+
+// Reassembler collects the fragments sent by one session. Groups that are not
+// completed within Timeout are dropped, and no more than limit bytes of
+// incomplete groups are kept at a time.
+type Reassembler struct {
+	mu     sync.Mutex
+	groups map[uint16]*group
+	size   int
+	limit  int
+}
+
+// Add stores a fragment and returns the full payload once every fragment of
+// its group has arrived.
+func (r *Reassembler) Add(packet protocol.Packet) (payload []byte, complete bool, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	r.removeExpired(now)
+
+	fragment := packet.Fragment
+	g, ok := r.groups[fragment.ID]
+	if ok && len(g.parts) != int(fragment.Count) {
+		r.remove(fragment.ID, g)
+		ok = false
+	}
+	if !ok {
+		g = &group{parts: make([][]byte, fragment.Count), arrived: make([]bool, fragment.Count), createdAt: now}
+		r.groups[fragment.ID] = g
+	}
+
+	if g.arrived[fragment.Index] {
+		return nil, false, nil
+	}
+	if r.size+len(packet.Payload) > r.limit {
+		r.remove(fragment.ID, g)
+		return nil, false, errors.ErrFragmentLimit
+	}
+
+	g.parts[fragment.Index] = append([]byte(nil), packet.Payload...)
+	g.arrived[fragment.Index] = true
+	g.received++
+	g.size += len(packet.Payload)
+	r.size += len(packet.Payload)
+
+	if g.received < len(g.parts) {
+		return nil, false, nil
+	}
+
+	payload = make([]byte, 0, g.size)
+	for _, part := range g.parts {
+		payload = append(payload, part...)
+	}
+	r.remove(fragment.ID, g)
+
+	return payload, true, nil
+}
+
+func (r *Reassembler) removeExpired(now time.Time) {
+	for id, g := range r.groups {
+		if now.Sub(g.createdAt) > Timeout {
+			r.remove(id, g)
+		}
+	}
+}
+
+func (r *Reassembler) remove(id uint16, g *group) {
+	r.size -= g.size
+	delete(r.groups, id)
+}
+
+func NewReassembler(limit int) *Reassembler {
+	return &Reassembler{
+		groups: make(map[uint16]*group),
+		limit:  limit,
+	}
+}
@@ -0,0 +1,89 @@
+package fragment
+
+import (
+	"github.com/ascenmmo/udp-server/pkg/errors"
+	"github.com/ascenmmo/udp-server/pkg/protocol"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func split(t *testing.T, size int, id uint16) []protocol.Packet {
+	payload := make([]byte, size)
+	for i := range payload {
+		payload[i] = byte(i)
+	}
+	fragments, err := protocol.NewPacket(protocol.TypeData, 1, payload).Split(500, id)
+	assert.NoError(t, err)
+	return fragments
+}
+
+func TestReassemble(t *testing.T) {
+	r := NewReassembler(1 << 20)
+	fragments := split(t, 2000, 1)
+
+	for i := len(fragments) - 1; i > 0; i-- {
+		_, complete, err := r.Add(fragments[i])
+		assert.NoError(t, err)
+		assert.False(t, complete)
+	}
+	_, complete, err := r.Add(fragments[1])
+	assert.NoError(t, err)
+	assert.False(t, complete)
+
+	payload, complete, err := r.Add(fragments[0])
+	assert.NoError(t, err)
+	assert.True(t, complete)
+	assert.Len(t, payload, 2000)
+	assert.Equal(t, byte(1999%256), payload[1999])
+	assert.Equal(t, 0, r.size)
+}
+
+func TestReassembleDuplicateEmptyFragment(t *testing.T) {
+	r := NewReassembler(1 << 20)
+	fragments := split(t, 1000, 1)
+	assert.Len(t, fragments, 3)
+	fragments[1].Payload = nil
+
+	for range 2 {
+		_, complete, err := r.Add(fragments[1])
+		assert.NoError(t, err)
+		assert.False(t, complete)
+	}
+	_, complete, err := r.Add(fragments[0])
+	assert.NoError(t, err)
+	assert.False(t, complete, "a duplicated empty fragment does not stand for a missing one")
+
+	payload, complete, err := r.Add(fragments[2])
+	assert.NoError(t, err)
+	assert.True(t, complete)
+	assert.Len(t, payload, len(fragments[0].Payload)+len(fragments[2].Payload))
+}
+
+func TestReassembleLimit(t *testing.T) {
+	r := NewReassembler(1000)
+	fragments := split(t, 2000, 1)
+
+	_, _, err := r.Add(fragments[0])
+	assert.NoError(t, err)
+	_, _, err = r.Add(fragments[1])
+	assert.NoError(t, err)
+	_, _, err = r.Add(fragments[2])
+	assert.Equal(t, errors.ErrFragmentLimit, err)
+	assert.Empty(t, r.groups)
+	assert.Equal(t, 0, r.size)
+}
+
+func TestReassembleTimeout(t *testing.T) {
+	r := NewReassembler(1 << 20)
+	fragments := split(t, 2000, 1)
+
+	_, _, err := r.Add(fragments[0])
+	assert.NoError(t, err)
+	r.groups[1].createdAt = time.Now().Add(-Timeout * 2)
+
+	_, _, err = r.Add(split(t, 2000, 2)[0])
+	assert.NoError(t, err)
+	assert.Len(t, r.groups, 1)
+	assert.NotNil(t, r.groups[2])
+}
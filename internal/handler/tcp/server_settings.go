@@ -11,6 +11,8 @@ import (
 type ServerSettings struct {
 	rateLimit utils.RateLimit
 	server    service.Service
+	tcpPort   string
+	udpPort   string
 }
 
 func (r *ServerSettings) GetConnectionsNum(ctx context.Context, token string) (countConn int, exists bool, err error) {
@@ -36,7 +38,7 @@ func (r *ServerSettings) GetServerSettings(ctx context.Context, token string) (s
 		return settings, errors.ErrTooManyRequests
 	}
 
-	return types.NewSettings(), nil
+	return types.NewSettings(r.tcpPort, r.udpPort), nil
 }
 
 func (r *ServerSettings) CreateRoom(ctx context.Context, token string, createRoom types.CreateRoomRequest) (err error) {
@@ -52,6 +54,134 @@ func (r *ServerSettings) GetDeletedRooms(ctx context.Context, token string, ids
 	return r.server.GetDeletedRooms(token, ids)
 }
 
-func NewServerSettings(rateLimit utils.RateLimit, server service.Service) *ServerSettings {
-	return &ServerSettings{rateLimit: rateLimit, server: server}
+func (r *ServerSettings) GetRoomStats(ctx context.Context, token string) (stats types.RoomStats, err error) {
+	limited := r.rateLimit.IsLimited(token)
+	if limited {
+		return stats, errors.ErrTooManyRequests
+	}
+	return r.server.GetRoomStats(token)
+}
+
+func (r *ServerSettings) GetLinkQuality(ctx context.Context, token string) (quality []types.LinkQuality, err error) {
+	limited := r.rateLimit.IsLimited(token)
+	if limited {
+		return nil, errors.ErrTooManyRequests
+	}
+	return r.server.GetLinkQuality(token)
+}
+
+func (r *ServerSettings) SetGameSettings(ctx context.Context, token string, settings types.GameSettings) (err error) {
+	limited := r.rateLimit.IsLimited(token)
+	if limited {
+		return errors.ErrTooManyRequests
+	}
+	return r.server.SetGameSettings(token, settings)
+}
+
+func (r *ServerSettings) GetGameSettings(ctx context.Context, token string) (settings types.GameSettings, err error) {
+	limited := r.rateLimit.IsLimited(token)
+	if limited {
+		return settings, errors.ErrTooManyRequests
+	}
+	return r.server.GetGameSettings(token)
+}
+
+func (r *ServerSettings) JoinGroup(ctx context.Context, token string, name string) (err error) {
+	limited := r.rateLimit.IsLimited(token)
+	if limited {
+		return errors.ErrTooManyRequests
+	}
+	return r.server.JoinGroup(token, name)
+}
+
+func (r *ServerSettings) LeaveGroup(ctx context.Context, token string, name string) (err error) {
+	limited := r.rateLimit.IsLimited(token)
+	if limited {
+		return errors.ErrTooManyRequests
+	}
+	return r.server.LeaveGroup(token, name)
+}
+
+func (r *ServerSettings) GetGroups(ctx context.Context, token string) (groups []types.Group, err error) {
+	limited := r.rateLimit.IsLimited(token)
+	if limited {
+		return nil, errors.ErrTooManyRequests
+	}
+	return r.server.GetGroups(token)
+}
+
+func (r *ServerSettings) ListRooms(ctx context.Context, token string, listRooms types.ListRoomsRequest) (rooms types.RoomList, err error) {
+	limited := r.rateLimit.IsLimited(token)
+	if limited {
+		return rooms, errors.ErrTooManyRequests
+	}
+	return r.server.ListRooms(token, listRooms)
+}
+
+func (r *ServerSettings) GetRoom(ctx context.Context, token string, room types.RoomKey) (roomInfo types.RoomInfo, err error) {
+	limited := r.rateLimit.IsLimited(token)
+	if limited {
+		return roomInfo, errors.ErrTooManyRequests
+	}
+	return r.server.GetRoom(token, room)
+}
+
+func (r *ServerSettings) CloseRoom(ctx context.Context, token string, closeRoom types.CloseRoomRequest) (err error) {
+	limited := r.rateLimit.IsLimited(token)
+	if limited {
+		return errors.ErrTooManyRequests
+	}
+	return r.server.CloseRoom(token, closeRoom)
+}
+
+func (r *ServerSettings) UpdateRoom(ctx context.Context, token string, updateRoom types.UpdateRoomRequest) (err error) {
+	limited := r.rateLimit.IsLimited(token)
+	if limited {
+		return errors.ErrTooManyRequests
+	}
+	return r.server.UpdateRoom(token, updateRoom)
+}
+
+func (r *ServerSettings) KickUser(ctx context.Context, token string, kickUser types.KickUserRequest) (err error) {
+	limited := r.rateLimit.IsLimited(token)
+	if limited {
+		return errors.ErrTooManyRequests
+	}
+	return r.server.KickUser(token, kickUser)
+}
+
+func (r *ServerSettings) BanUser(ctx context.Context, token string, banUser types.BanUserRequest) (err error) {
+	limited := r.rateLimit.IsLimited(token)
+	if limited {
+		return errors.ErrTooManyRequests
+	}
+	return r.server.BanUser(token, banUser)
+}
+
+func (r *ServerSettings) GetRoomBans(ctx context.Context, token string, room types.RoomKey) (bans []types.Ban, err error) {
+	limited := r.rateLimit.IsLimited(token)
+	if limited {
+		return nil, errors.ErrTooManyRequests
+	}
+	return r.server.GetRoomBans(token, room)
+}
+
+func (r *ServerSettings) GetGameBans(ctx context.Context, token string) (bans []types.Ban, err error) {
+	limited := r.rateLimit.IsLimited(token)
+	if limited {
+		return nil, errors.ErrTooManyRequests
+	}
+	return r.server.GetGameBans(token)
+}
+
+func (r *ServerSettings) SetRoomMetadata(ctx context.Context, token string, setRoomMetadata types.SetRoomMetadataRequest) (version uint64, err error) {
+	limited := r.rateLimit.IsLimited(token)
+	if limited {
+		return 0, errors.ErrTooManyRequests
+	}
+	return r.server.SetRoomMetadata(token, setRoomMetadata)
+}
+
+func NewServerSettings(rateLimit utils.RateLimit, server service.Service, tcpPort, udpPort string) *ServerSettings {
+	return &ServerSettings{rateLimit: rateLimit, server: server, tcpPort: tcpPort, udpPort: udpPort}
 }
@@ -0,0 +1,26 @@
+package udp
+
+import "sync"
+
+// bufferPool holds the receive buffers. A buffer belongs to the packet read
+// into it until a worker has handled the packet and written every message
+// built from it, because the decoded payload still points into the buffer.
+// Everything kept longer copies the payload: the reliable channel when it
+// receives out of order or sends, and the fragment reassembler.
+var bufferPool = sync.Pool{
+	New: func() any {
+		buf := make([]byte, bufferSize)
+		return &buf
+	},
+}
+
+func getBuffer() *[]byte {
+	return bufferPool.Get().(*[]byte)
+}
+
+func putBuffer(buf *[]byte) {
+	if buf == nil {
+		return
+	}
+	bufferPool.Put(buf)
+}
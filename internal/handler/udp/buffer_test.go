@@ -0,0 +1,118 @@
+package udp
+
+import (
+	"bytes"
+	"context"
+	"github.com/ascenmmo/udp-server/internal/connection"
+	"github.com/ascenmmo/udp-server/internal/service"
+	memoryDB "github.com/ascenmmo/udp-server/internal/storage"
+	"github.com/ascenmmo/udp-server/internal/utils"
+	"github.com/ascenmmo/udp-server/pkg/api/types"
+	"github.com/ascenmmo/udp-server/pkg/protocol"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// checkService checks, after a delay, that every byte of each payload is
+// still its length, which is how the datagrams are built.
+type checkService struct {
+	service.Service
+
+	delay     time.Duration
+	handled   atomic.Int64
+	corrupted atomic.Int64
+}
+
+func (s *checkService) RoutingKey(ds connection.DataSender, _ protocol.Packet) string {
+	return ds.GetID()
+}
+
+func (s *checkService) GetUsersAndMessages(_ connection.DataSender, packet protocol.Packet) ([]types.Message, error) {
+	time.Sleep(s.delay)
+	if !bytes.Equal(packet.Payload, bytes.Repeat([]byte{byte(len(packet.Payload))}, len(packet.Payload))) {
+		s.corrupted.Add(1)
+	}
+	s.handled.Add(1)
+	return nil, nil
+}
+
+func newTestWorker(t testing.TB, ctx context.Context, srv service.Service) (*WorkerUDP, *net.UDPConn) {
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	assert.NoError(t, err)
+
+	s := newSocket(conn)
+	w := &WorkerUDP{
+		service:   srv,
+		sockets:   []*socket{s},
+		batches:   []*connection.BatchConn{s.batch},
+		queues:    []*queue{newQueue(1024)},
+		rateLimit: utils.NewRateLimit(1<<30, memoryDB.NewMemoryDb(ctx, 1)),
+		logger:    zerolog.Nop(),
+		legacy:    true,
+	}
+
+	client, err := net.DialUDP("udp", nil, conn.LocalAddr().(*net.UDPAddr))
+	assert.NoError(t, err)
+
+	return w, client
+}
+
+func TestReceiveBuffersAreNotShared(t *testing.T) {
+	const packets = 500
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	srv := &checkService{delay: time.Millisecond}
+	w, client := newTestWorker(t, ctx, srv)
+	defer client.Close()
+
+	go w.Listener(ctx)
+	go w.sendWorker(ctx, w.queues[0])
+
+	for i := 0; i < packets; i++ {
+		size := 100 + i%150
+		_, err := client.Write(bytes.Repeat([]byte{byte(size)}, size))
+		assert.NoError(t, err)
+		time.Sleep(time.Microsecond * 50)
+	}
+
+	// Loopback may still drop a few datagrams, so wait until the worker is idle.
+	for handled := int64(-1); handled != srv.handled.Load(); {
+		handled = srv.handled.Load()
+		time.Sleep(time.Millisecond * 100)
+	}
+	assert.Greater(t, srv.handled.Load(), int64(packets*9/10))
+	assert.Zero(t, srv.corrupted.Load())
+
+	w.sockets[0].conn.Close()
+}
+
+func BenchmarkReceive(b *testing.B) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	w, client := newTestWorker(b, ctx, &checkService{})
+	defer client.Close()
+	defer w.sockets[0].conn.Close()
+
+	datagram := bytes.Repeat([]byte{200}, 200)
+	r := newReceiver()
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(datagram)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, err := client.Write(datagram)
+		if err != nil {
+			b.Fatal(err)
+		}
+		w.receive(w.sockets[0], r)
+		msg, _ := w.queues[0].pop(ctx)
+		putBuffer(msg.buffer)
+	}
+}
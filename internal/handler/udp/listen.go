@@ -0,0 +1,59 @@
+package udp
+
+import (
+	"context"
+	"net"
+	"net/netip"
+)
+
+// listen opens count sockets bound to addr. They share the port through
+// SO_REUSEPORT, and the kernel spreads client flows across them. Without
+// SO_REUSEPORT a single socket is opened.
+func listen(addr string, count int) (conns []*net.UDPConn, err error) {
+	if !reusePortSupported {
+		count = 1
+	}
+
+	var config net.ListenConfig
+	if count > 1 {
+		config.Control = reusePort
+	}
+
+	network := listenNetwork(addr)
+	for i := 0; i < count; i++ {
+		conn, err := config.ListenPacket(context.Background(), network, addr)
+		if err != nil {
+			for _, c := range conns {
+				c.Close()
+			}
+			return nil, err
+		}
+		conns = append(conns, conn.(*net.UDPConn))
+
+		// The next sockets take the port the first one got.
+		addr = conn.LocalAddr().String()
+	}
+
+	return conns, nil
+}
+
+// listenNetwork picks the network for a listen address. IPv4 and IPv6
+// literals get a socket of their family only, so "0.0.0.0:4500" and
+// "[::]:4500" can be listened on together. Any other host, including an
+// empty one, gets a dual-stack socket where the system supports it.
+func listenNetwork(addr string) string {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return "udp"
+	}
+
+	ip, err := netip.ParseAddr(host)
+	switch {
+	case err != nil:
+		return "udp"
+	case ip.Is4():
+		return "udp4"
+	default:
+		return "udp6"
+	}
+}
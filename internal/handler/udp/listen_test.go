@@ -0,0 +1,112 @@
+package udp
+
+import (
+	"context"
+	memoryDB "github.com/ascenmmo/udp-server/internal/storage"
+	"github.com/ascenmmo/udp-server/pkg/errors"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"net"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestListenSharesPort(t *testing.T) {
+	const (
+		sockets = 4
+		clients = 64
+	)
+
+	conns, err := listen("127.0.0.1:0", sockets)
+	assert.NoError(t, err)
+	if !reusePortSupported {
+		assert.Len(t, conns, 1)
+		return
+	}
+	assert.Len(t, conns, sockets)
+
+	port := conns[0].LocalAddr().(*net.UDPAddr).Port
+	received := make([]int, len(conns))
+	var wg sync.WaitGroup
+	for i, conn := range conns {
+		defer conn.Close()
+		assert.Equal(t, port, conn.LocalAddr().(*net.UDPAddr).Port)
+
+		wg.Add(1)
+		go func(i int, conn *net.UDPConn) {
+			defer wg.Done()
+			buf := make([]byte, 16)
+			for {
+				assert.NoError(t, conn.SetReadDeadline(time.Now().Add(time.Millisecond*500)))
+				if _, _, err := conn.ReadFromUDP(buf); err != nil {
+					return
+				}
+				received[i]++
+			}
+		}(i, conn)
+	}
+
+	for i := 0; i < clients; i++ {
+		client, err := net.DialUDP("udp", nil, conns[0].LocalAddr().(*net.UDPAddr))
+		assert.NoError(t, err)
+		_, err = client.Write([]byte{byte(i)})
+		assert.NoError(t, err)
+		client.Close()
+	}
+	wg.Wait()
+
+	total, used := 0, 0
+	for _, n := range received {
+		total += n
+		if n > 0 {
+			used++
+		}
+	}
+	assert.Equal(t, clients, total)
+	assert.Greater(t, used, 1, "the kernel should spread clients across the sockets")
+}
+
+func TestListenNetwork(t *testing.T) {
+	assert.Equal(t, "udp", listenNetwork(":4500"))
+	assert.Equal(t, "udp", listenNetwork("localhost:4500"))
+	assert.Equal(t, "udp4", listenNetwork("0.0.0.0:4500"))
+	assert.Equal(t, "udp6", listenNetwork("[::]:4500"))
+	assert.Equal(t, "udp6", listenNetwork("[fe80::1%eth0]:4500"))
+}
+
+func TestWorkerListensOnEveryAddress(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	w, err := NewWorkerUDP([]string{"127.0.0.1:0", "[::1]:0"}, 1, &checkService{}, 1000, memoryDB.NewMemoryDb(ctx, 1), true, zerolog.Nop())
+	assert.NoError(t, err)
+	assert.Len(t, w.sockets, 2)
+
+	r := newReceiver()
+	ids := make(map[string]bool)
+	for _, s := range w.sockets {
+		defer s.conn.Close()
+
+		client, err := net.DialUDP("udp", nil, s.conn.LocalAddr().(*net.UDPAddr))
+		assert.NoError(t, err)
+		_, err = client.Write([]byte("data"))
+		assert.NoError(t, err)
+		client.Close()
+
+		w.receive(s, r)
+		var msg ChanUDPMessage
+		for _, q := range w.queues {
+			if q.len() > 0 {
+				msg, _ = q.pop(ctx)
+			}
+		}
+		putBuffer(msg.buffer)
+		assert.Contains(t, msg.client.GetID(), s.local)
+		ids[msg.client.GetID()] = true
+	}
+	assert.Len(t, ids, 2)
+
+	_, err = NewWorkerUDP(nil, 1, &checkService{}, 1, memoryDB.NewMemoryDb(ctx, 1), true, zerolog.Nop())
+	assert.ErrorIs(t, err, errors.ErrNoListenAddresses)
+}
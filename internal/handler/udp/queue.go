@@ -0,0 +1,117 @@
+package udp
+
+import (
+	"context"
+	"github.com/ascenmmo/udp-server/pkg/protocol"
+	"sync/atomic"
+)
+
+// weights is the share of the packets a worker takes from each class when
+// all of them are waiting, by rank.
+var weights = [protocol.Priorities]int{1, 2, 4, 8}
+
+// queue is the input of one worker: a channel per priority class, ordered by
+// rank, sharing one bound.
+type queue struct {
+	classes [protocol.Priorities]chan ChanUDPMessage
+	ready   chan struct{}
+	size    int64
+	length  atomic.Int64
+	drops   [protocol.Priorities]atomic.Uint64
+
+	// credits is only used by the worker.
+	credits [protocol.Priorities]int
+}
+
+func newQueue(size int) *queue {
+	q := &queue{
+		ready:   make(chan struct{}, 1),
+		size:    int64(max(1, size)),
+		credits: weights,
+	}
+	for i := range q.classes {
+		q.classes[i] = make(chan ChanUDPMessage, q.size)
+	}
+	return q
+}
+
+// push queues msg. When the queue is full, a packet of the lowest class below
+// msg's is dropped to make room; when there is none, msg is dropped. It
+// reports whether msg was queued.
+func (q *queue) push(msg ChanUDPMessage) bool {
+	priority := msg.request.Priority()
+	for {
+		n := q.length.Load()
+		if n < q.size {
+			if q.length.CompareAndSwap(n, n+1) {
+				break
+			}
+			continue
+		}
+		if !q.shed(priority.Rank()) {
+			q.drops[priority].Add(1)
+			return false
+		}
+	}
+
+	q.classes[priority.Rank()] <- msg
+	select {
+	case q.ready <- struct{}{}:
+	default:
+	}
+	return true
+}
+
+// shed drops the oldest packet of the lowest class below rank.
+func (q *queue) shed(rank int) bool {
+	for i := 0; i < rank; i++ {
+		select {
+		case msg := <-q.classes[i]:
+			q.length.Add(-1)
+			q.drops[msg.request.Priority()].Add(1)
+			putBuffer(msg.buffer)
+			return true
+		default:
+		}
+	}
+	return false
+}
+
+// pop waits for the next packet. Classes are served by weight, so a busy
+// class delays but never starves the ones below it. Each class is first in,
+// first out; across classes the order is not kept.
+func (q *queue) pop(ctx context.Context) (msg ChanUDPMessage, ok bool) {
+	for {
+		if msg, ok = q.next(); ok {
+			return msg, true
+		}
+		select {
+		case <-ctx.Done():
+			return msg, false
+		case <-q.ready:
+		}
+	}
+}
+
+func (q *queue) next() (msg ChanUDPMessage, ok bool) {
+	for range 2 {
+		for rank := len(q.classes) - 1; rank >= 0; rank-- {
+			if q.credits[rank] == 0 {
+				continue
+			}
+			select {
+			case msg = <-q.classes[rank]:
+				q.credits[rank]--
+				q.length.Add(-1)
+				return msg, true
+			default:
+			}
+		}
+		q.credits = weights
+	}
+	return msg, false
+}
+
+func (q *queue) len() int {
+	return int(q.length.Load())
+}
@@ -0,0 +1,84 @@
+package udp
+
+import (
+	"context"
+	"github.com/ascenmmo/udp-server/pkg/protocol"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func priorityMessage(priority protocol.Priority, seq uint32) ChanUDPMessage {
+	packet := protocol.NewPacket(protocol.TypeData, seq, nil)
+	packet.SetPriority(priority)
+	return ChanUDPMessage{request: packet}
+}
+
+func TestQueueServesClassesByWeight(t *testing.T) {
+	q := newQueue(100)
+	for i := 0; i < 16; i++ {
+		for _, priority := range []protocol.Priority{protocol.PriorityLow, protocol.PriorityNormal, protocol.PriorityHigh, protocol.PriorityCritical} {
+			assert.True(t, q.push(priorityMessage(priority, uint32(i))))
+		}
+	}
+
+	served := make(map[protocol.Priority]int)
+	for i := 0; i < 15; i++ {
+		msg, ok := q.pop(context.Background())
+		assert.True(t, ok)
+		served[msg.request.Priority()]++
+	}
+	assert.Equal(t, map[protocol.Priority]int{
+		protocol.PriorityCritical: 8,
+		protocol.PriorityHigh:     4,
+		protocol.PriorityNormal:   2,
+		protocol.PriorityLow:      1,
+	}, served)
+	assert.Equal(t, 64-15, q.len())
+}
+
+func TestQueueShedsLowestClassFirst(t *testing.T) {
+	q := newQueue(3)
+	assert.True(t, q.push(priorityMessage(protocol.PriorityLow, 1)))
+	assert.True(t, q.push(priorityMessage(protocol.PriorityNormal, 2)))
+	assert.True(t, q.push(priorityMessage(protocol.PriorityLow, 3)))
+
+	assert.True(t, q.push(priorityMessage(protocol.PriorityCritical, 4)), "the oldest low packet makes room")
+	assert.True(t, q.push(priorityMessage(protocol.PriorityHigh, 5)), "then the next one")
+	assert.True(t, q.push(priorityMessage(protocol.PriorityHigh, 6)), "then a normal one")
+	assert.False(t, q.push(priorityMessage(protocol.PriorityLow, 7)), "nothing is lower than a low packet")
+	assert.False(t, q.push(priorityMessage(protocol.PriorityHigh, 8)))
+
+	assert.Equal(t, uint64(3), q.drops[protocol.PriorityLow].Load())
+	assert.Equal(t, uint64(1), q.drops[protocol.PriorityNormal].Load())
+	assert.Equal(t, uint64(1), q.drops[protocol.PriorityHigh].Load())
+
+	var sequences []uint32
+	for q.len() > 0 {
+		msg, _ := q.pop(context.Background())
+		sequences = append(sequences, msg.request.Header.Sequence)
+	}
+	assert.Equal(t, []uint32{4, 5, 6}, sequences)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, ok := q.pop(ctx)
+	assert.False(t, ok)
+}
+
+func TestQueueKeepsOrderWithinClass(t *testing.T) {
+	q := newQueue(100)
+	for i := 1; i <= 40; i++ {
+		priority := []protocol.Priority{protocol.PriorityNormal, protocol.PriorityCritical, protocol.PriorityLow}[i%3]
+		assert.True(t, q.push(priorityMessage(priority, uint32(i))))
+	}
+
+	last := make(map[protocol.Priority]uint32)
+	for q.len() > 0 {
+		msg, ok := q.pop(context.Background())
+		assert.True(t, ok)
+		priority := msg.request.Priority()
+		assert.Greater(t, msg.request.Header.Sequence, last[priority], "packets of a class leave in the order they arrived")
+		last[priority] = msg.request.Header.Sequence
+	}
+	assert.Len(t, last, 3)
+}
@@ -0,0 +1,20 @@
+//go:build linux
+
+package udp
+
+import (
+	"golang.org/x/sys/unix"
+	"syscall"
+)
+
+const reusePortSupported = true
+
+func reusePort(_, _ string, c syscall.RawConn) (err error) {
+	ctrlErr := c.Control(func(fd uintptr) {
+		err = unix.SetsockoptInt(int(fd), unix.SOL_SOCKET, unix.SO_REUSEPORT, 1)
+	})
+	if ctrlErr != nil {
+		return ctrlErr
+	}
+	return err
+}
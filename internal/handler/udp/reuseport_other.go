@@ -0,0 +1,13 @@
+//go:build !linux
+
+package udp
+
+import "syscall"
+
+// Other systems either lack SO_REUSEPORT or do not balance datagrams between
+// the sockets, so the server listens on one socket there.
+const reusePortSupported = false
+
+func reusePort(_, _ string, _ syscall.RawConn) error {
+	return nil
+}
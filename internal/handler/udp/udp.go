@@ -6,165 +6,372 @@ import (
 	"github.com/ascenmmo/udp-server/internal/service"
 	memoryDB "github.com/ascenmmo/udp-server/internal/storage"
 	"github.com/ascenmmo/udp-server/internal/utils"
+	"github.com/ascenmmo/udp-server/pkg/api/types"
+	"github.com/ascenmmo/udp-server/pkg/errors"
+	"github.com/ascenmmo/udp-server/pkg/protocol"
 	"github.com/rs/zerolog"
+	"hash/fnv"
 	"net"
 	"runtime"
 	"time"
 )
 
 const (
-	bufferSize = 4096
+	bufferSize          = 65535
+	maintenanceInterval = time.Second / 4
 )
 
 type WorkerUDP struct {
 	service   service.Service
-	conn      *net.UDPConn
-	chMsg     []chan ChanUDPMessage
+	sockets   []*socket
+	batches   []*connection.BatchConn
+	queues    []*queue
 	rateLimit utils.RateLimit
 	logger    zerolog.Logger
-	count     int
+	legacy    bool
+}
+
+// socket is one of the sockets bound to a listen address, with its own
+// reader.
+type socket struct {
+	conn  *net.UDPConn
+	local string
+	batch *connection.BatchConn
+}
+
+func newSocket(conn *net.UDPConn) *socket {
+	return &socket{
+		conn:  conn,
+		local: conn.LocalAddr().String(),
+		batch: connection.NewBatchConn(conn),
+	}
 }
 
 type ChanUDPMessage struct {
 	client  connection.DataSender
-	request []byte
+	request protocol.Packet
+	buffer  *[]byte
+}
+
+// receiver holds the pooled buffers the next batch is read into.
+type receiver struct {
+	messages []connection.Message
+	buffers  []*[]byte
+}
+
+// take hands out the buffer of message i and puts a fresh one in its place.
+func (r *receiver) take(i int) *[]byte {
+	buffer := r.buffers[i]
+	r.buffers[i] = getBuffer()
+	r.messages[i].Buffer = *r.buffers[i]
+	return buffer
 }
 
+func newReceiver() *receiver {
+	r := &receiver{
+		messages: make([]connection.Message, connection.BatchSize),
+		buffers:  make([]*[]byte, connection.BatchSize),
+	}
+	for i := range r.buffers {
+		r.buffers[i] = getBuffer()
+		r.messages[i].Buffer = *r.buffers[i]
+	}
+	return r
+}
+
+// Listener runs a reader for every socket and returns when the first of them
+// stops.
 func (w *WorkerUDP) Listener(ctx context.Context) error {
-	defer w.conn.Close()
+	errs := make(chan error, len(w.sockets))
+	for _, s := range w.sockets {
+		go func(s *socket) {
+			errs <- w.listen(ctx, s)
+		}(s)
+	}
+	return <-errs
+}
 
-	buffer := make([]byte, bufferSize)
+func (w *WorkerUDP) listen(ctx context.Context, s *socket) error {
+	defer s.conn.Close()
+
+	r := newReceiver()
 	for {
 		select {
 		case <-ctx.Done():
 			return ctx.Err()
 		default:
-			n, clientAddr, err := w.conn.ReadFromUDP(buffer)
-			if err != nil {
-				w.logger.Error().Err(err).Msg("Listener ReadFromUDP")
-				continue
-			}
+			w.receive(s, r)
+		}
+	}
+}
 
-			if w.rateLimit.IsLimited(clientAddr.String()) {
-				continue
-			}
+// receive reads a batch of datagrams into pooled buffers and hands each to a
+// worker.
+func (w *WorkerUDP) receive(s *socket, r *receiver) {
+	n, err := s.batch.ReadBatch(r.messages)
+	if err != nil {
+		w.logger.Error().Err(err).Msg("Listener ReadFromUDP")
+		return
+	}
 
-			if n == 0 {
-				continue
-			}
+	for i := 0; i < n; i++ {
+		w.receiveDatagram(s, r.take(i), r.messages[i].N, r.messages[i].Addr)
+	}
+}
 
-			err = w.handleConnection(clientAddr, buffer[:n])
-			if err != nil {
-				w.logger.Error().Err(err).Msg("Listener handleConnection")
-				continue
-			}
-		}
+// receiveDatagram decodes a datagram and hands it to a worker. The buffer
+// goes back to the pool here when the datagram is dropped, and in sendWorker
+// otherwise.
+func (w *WorkerUDP) receiveDatagram(s *socket, buffer *[]byte, n int, clientAddr *net.UDPAddr) {
+	if w.rateLimit.IsLimited(clientAddr.String()) || n == 0 {
+		putBuffer(buffer)
+		return
+	}
+
+	packet, err := w.decode((*buffer)[:n])
+	if err != nil {
+		putBuffer(buffer)
+		w.logger.Debug().Err(err).Str("addr", clientAddr.String()).Msg("Listener decode")
+		return
+	}
+
+	err = w.handleConnection(s, clientAddr, packet, buffer)
+	if err != nil {
+		w.logger.Error().Err(err).Msg("Listener handleConnection")
 	}
 }
 
 func (w *WorkerUDP) Sender(ctx context.Context) {
 	go w.printer()
-	for _, v := range w.chMsg {
-		go w.sendWorker(ctx, v)
+	go w.maintenance(ctx)
+	go w.ticker(ctx)
+	for _, q := range w.queues {
+		go w.sendWorker(ctx, q)
+	}
+}
+
+func (w *WorkerUDP) decode(buf []byte) (protocol.Packet, error) {
+	if protocol.IsFramed(buf) {
+		return protocol.Decode(buf)
+	}
+	if !w.legacy {
+		return protocol.Packet{}, errors.ErrLegacyProtocolDisabled
 	}
+	return protocol.Packet{Legacy: true, Payload: buf}, nil
 }
 
-func (w *WorkerUDP) handleConnection(clientAddr *net.UDPAddr, buf []byte) error {
+// handleConnection queues the packet for a worker. The workers are shared by
+// every socket, so the packets of a room are handled in order, within each
+// priority class, whichever socket they arrive on. The connection is bound to the socket the packet
+// arrived on, so replies leave through it. When the worker's queue is full,
+// the lowest priority class is shed first.
+func (w *WorkerUDP) handleConnection(s *socket, clientAddr *net.UDPAddr, packet protocol.Packet, buffer *[]byte) error {
 	defer func() {
 		if r := recover(); r != nil {
+			putBuffer(buffer)
 			w.logger.Error().Msgf("recover: %v", r)
 		}
 	}()
 
 	ds := connection.DataSender(&connection.UDPConnection{
 		ClientAddr: clientAddr,
-		Conn:       w.conn,
+		Conn:       s.conn,
+		Local:      s.local,
 	})
 
-	select {
-	case w.chMsg[w.counterChen()] <- ChanUDPMessage{
+	worker := 0
+	if len(w.queues) > 1 {
+		worker = w.route(w.service.RoutingKey(ds, packet))
+	}
+
+	queued := w.queues[worker].push(ChanUDPMessage{
 		client:  ds,
-		request: buf,
-	}:
-	default:
-		return nil //errors.New("counterChen is full")
+		request: packet,
+		buffer:  buffer,
+	})
+	if !queued {
+		putBuffer(buffer)
 	}
 
 	return nil
 }
 
-func (w *WorkerUDP) sendWorker(ctx context.Context, ch chan ChanUDPMessage) {
+func (w *WorkerUDP) sendWorker(ctx context.Context, q *queue) {
+	for {
+		chMsg, ok := q.pop(ctx)
+		if !ok {
+			return
+		}
+		messages, err := w.service.GetUsersAndMessages(chMsg.client, chMsg.request)
+		if err != nil {
+			putBuffer(chMsg.buffer)
+			w.logger.Warn().Err(err).Msg("senderWorker GetUsersAndMessages")
+			continue
+		}
+		w.write(messages)
+		putBuffer(chMsg.buffer)
+	}
+}
+
+// maintenance pings the clients and removes users that stopped sending,
+// notifying their rooms.
+func (w *WorkerUDP) maintenance(ctx context.Context) {
+	ticker := time.NewTicker(maintenanceInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.write(w.service.PingUsers())
+			w.write(w.service.RemoveIdleUsers())
+		}
+	}
+}
+
+// ticker sends the bundles of the rooms in tick mode.
+func (w *WorkerUDP) ticker(ctx context.Context) {
+	ticker := time.NewTicker(service.TickResolution)
+	defer ticker.Stop()
+
 	for {
 		select {
 		case <-ctx.Done():
 			return
-		case chMsg := <-ch:
-			users, msg, err := w.service.GetUsersAndMessage(chMsg.client, chMsg.request)
+		case now := <-ticker.C:
+			w.write(w.service.Tick(now))
+		}
+	}
+}
+
+// write sends the messages to their users as one batch. A user that cannot
+// be written to is removed from the room, and the rest of the room is told.
+func (w *WorkerUDP) write(messages []types.Message) {
+	batch := connection.NewBatch(w.batches...)
+	for _, msg := range messages {
+		for _, user := range msg.Users {
+			err := user.WriteBatch(msg.Packet, batch)
 			if err != nil {
-				w.logger.Warn().Err(err).Msg("senderWorker GetUsersAndMessage")
-				continue
+				w.logger.Warn().Err(err).Interface("senderWorker WriteToUDP", user.ID)
+				w.removeUser(user)
 			}
-			if len(msg) == 0 || len(users) == 0 {
-				continue
+		}
+	}
+
+	for _, ds := range batch.Flush() {
+		user, ok := findUser(messages, ds)
+		if !ok {
+			continue
+		}
+		w.logger.Warn().Interface("senderWorker WriteToUDP", user.ID)
+		w.removeUser(user)
+	}
+}
+
+func (w *WorkerUDP) removeUser(user types.User) {
+	if user.Connection == nil {
+		return
+	}
+	removed, err := w.service.RemoveUser(user.Connection, user.ID)
+	if err != nil {
+		w.logger.Warn().Err(err).Interface("senderWorker  RemoveUser", user.ID)
+	}
+	w.write(removed)
+}
+
+// findUser returns the recipient whose datagram went to ds.
+func findUser(messages []types.Message, ds connection.DataSender) (types.User, bool) {
+	for _, msg := range messages {
+		for _, user := range msg.Users {
+			conn := user.Connection
+			if user.Session != nil {
+				conn = user.Session.Connection()
 			}
-			for _, user := range users {
-				err = user.Connection.Write(msg)
-				if err != nil {
-					w.logger.Warn().Err(err).Interface("senderWorker WriteToUDP", user.ID)
-					err := w.service.RemoveUser(chMsg.client, user.ID)
-					if err != nil {
-						w.logger.Warn().Err(err).Interface("senderWorker  RemoveUser", user.ID)
-					}
-				}
+			if conn != nil && conn.GetID() == ds.GetID() {
+				return user, true
 			}
 		}
 	}
+	return types.User{}, false
+}
+
+// route picks the worker for a routing key. Every packet of a room goes to
+// the same worker, so the room receives the packets of each priority class
+// in the order they arrived. Across classes a packet may overtake a packet of
+// a lower class that arrived before it: that is what the classes are for.
+// Clients that need order across classes send those packets reliable.
+func (w *WorkerUDP) route(key string) int {
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return int(h.Sum32() % uint32(len(w.queues)))
 }
 
-func (w *WorkerUDP) counterChen() int {
-	w.count = (w.count + 1) % len(w.chMsg)
-	return w.count
+// Drops returns the number of packets shed from the worker queues, by
+// priority class.
+func (w *WorkerUDP) Drops() (drops [protocol.Priorities]uint64) {
+	for _, q := range w.queues {
+		for i := range drops {
+			drops[i] += q.drops[i].Load()
+		}
+	}
+	return drops
 }
 
 func (w *WorkerUDP) printer() {
 	ticker := time.NewTicker(time.Second * 1)
 	counter := 0
 	for range ticker.C {
-		for _, v := range w.chMsg {
-			counter += len(v)
+		for _, q := range w.queues {
+			counter += q.len()
 		}
 		w.logger.Info().Interface("msges in chan", counter)
+		w.logger.Info().Interface("dropped by priority", w.Drops())
 		w.logger.Info().Interface("gorutins", runtime.NumGoroutine())
 		counter = 0
 	}
 }
 
-func NewWorkerUDP(addr string, service service.Service, rateLimit int, storage memoryDB.IMemoryDB, logger zerolog.Logger) (*WorkerUDP, error) {
-	chs := make([]chan ChanUDPMessage, runtime.NumCPU())
-	for i := range chs {
-		chs[i] = make(chan ChanUDPMessage, rateLimit)
-	}
+// NewWorkerUDP opens the given number of sockets on every listen address, one
+// per CPU when it is zero, and starts one worker per CPU shared by them.
+func NewWorkerUDP(addrs []string, sockets int, service service.Service, rateLimit int, storage memoryDB.IMemoryDB, legacy bool, logger zerolog.Logger) (*WorkerUDP, error) {
 	w := &WorkerUDP{
 		service:   service,
 		logger:    logger,
-		chMsg:     chs,
 		rateLimit: utils.NewRateLimit(rateLimit, storage),
+		legacy:    legacy,
 	}
 
-	addr = ":" + addr
-	udpAddr, err := net.ResolveUDPAddr("udp", addr)
-	if err != nil {
-		w.logger.Error().Err(err).Msg("Listener ResolveUDPAddr")
-		return nil, err
+	if len(addrs) == 0 {
+		return nil, errors.ErrNoListenAddresses
+	}
+	if sockets <= 0 {
+		sockets = runtime.NumCPU()
 	}
-	w.logger.Info().Msgf("Listener UDP Started on %s", addr)
 
-	conn, err := net.ListenUDP("udp", udpAddr)
-	if err != nil {
-		w.logger.Error().Err(err).Msg("Listener ListenUDP")
-		return nil, err
+	var conns []*net.UDPConn
+	for _, addr := range addrs {
+		opened, err := listen(addr, sockets)
+		if err != nil {
+			for _, conn := range conns {
+				conn.Close()
+			}
+			w.logger.Error().Err(err).Str("addr", addr).Msg("Listener ListenUDP")
+			return nil, err
+		}
+		conns = append(conns, opened...)
+		w.logger.Info().Msgf("Listener UDP Started on %s with %d sockets", addr, len(opened))
+	}
+
+	for _, conn := range conns {
+		s := newSocket(conn)
+		w.sockets = append(w.sockets, s)
+		w.batches = append(w.batches, s.batch)
+	}
+	w.queues = make([]*queue, runtime.NumCPU())
+	for i := range w.queues {
+		w.queues[i] = newQueue(rateLimit)
 	}
-	w.conn = conn
 
 	return w, nil
 }
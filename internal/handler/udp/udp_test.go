@@ -0,0 +1,152 @@
+package udp
+
+import (
+	"context"
+	"encoding/binary"
+	"github.com/ascenmmo/udp-server/internal/connection"
+	"github.com/ascenmmo/udp-server/internal/service"
+	memoryDB "github.com/ascenmmo/udp-server/internal/storage"
+	"github.com/ascenmmo/udp-server/pkg/api/types"
+	"github.com/ascenmmo/udp-server/pkg/protocol"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	"net"
+	"runtime"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// orderService records the order in which the packets of each room are
+// handled. The room is the first byte of the payload.
+type orderService struct {
+	service.Service
+
+	mu      sync.Mutex
+	handled map[byte][]uint32
+	wg      sync.WaitGroup
+}
+
+func (s *orderService) RoutingKey(_ connection.DataSender, packet protocol.Packet) string {
+	return "room:" + strconv.Itoa(int(packet.Payload[0]))
+}
+
+func (s *orderService) GetUsersAndMessages(_ connection.DataSender, packet protocol.Packet) ([]types.Message, error) {
+	defer s.wg.Done()
+	time.Sleep(time.Duration(rand.Intn(50)) * time.Microsecond)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	room := packet.Payload[0]
+	s.handled[room] = append(s.handled[room], packet.Header.Sequence)
+
+	return nil, nil
+}
+
+func TestRoomOrderUnderLoad(t *testing.T) {
+	const (
+		rooms   = 64
+		packets = 500
+		workers = 8
+	)
+
+	srv := &orderService{handled: make(map[byte][]uint32)}
+	s := &socket{}
+	w := &WorkerUDP{
+		service: srv,
+		sockets: []*socket{s},
+		queues:  make([]*queue, workers),
+		logger:  zerolog.Nop(),
+	}
+	for i := range w.queues {
+		w.queues[i] = newQueue(rooms * packets)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	for _, q := range w.queues {
+		go w.sendWorker(ctx, q)
+	}
+
+	srv.wg.Add(rooms * packets)
+	var senders sync.WaitGroup
+	for room := 0; room < rooms; room++ {
+		senders.Add(1)
+		go func(room int) {
+			defer senders.Done()
+			addr := &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 10000 + room}
+			for seq := uint32(1); seq <= packets; seq++ {
+				payload := binary.BigEndian.AppendUint32([]byte{byte(room)}, seq)
+				assert.NoError(t, w.handleConnection(s, addr, protocol.NewPacket(protocol.TypeData, seq, payload), nil))
+			}
+		}(room)
+	}
+	senders.Wait()
+	srv.wg.Wait()
+
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	assert.Len(t, srv.handled, rooms)
+	for room, sequences := range srv.handled {
+		assert.Len(t, sequences, packets, "room %d", room)
+		for i, seq := range sequences {
+			if !assert.Equal(t, uint32(i+1), seq, "room %d", room) {
+				break
+			}
+		}
+	}
+}
+
+func TestRouteIsStable(t *testing.T) {
+	w := &WorkerUDP{queues: make([]*queue, 8)}
+
+	for i := 0; i < 100; i++ {
+		key := "room:" + strconv.Itoa(i)
+		assert.Equal(t, w.route(key), w.route(key))
+		assert.Less(t, w.route(key), len(w.queues))
+	}
+}
+
+func TestRoomOrderAcrossSockets(t *testing.T) {
+	for _, sockets := range []int{runtime.NumCPU(), 4} {
+		t.Run(strconv.Itoa(sockets), func(t *testing.T) {
+			testRoomOrderAcrossSockets(t, sockets)
+		})
+	}
+}
+
+func testRoomOrderAcrossSockets(t *testing.T, count int) {
+	const packets = 2000
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	srv := &orderService{handled: make(map[byte][]uint32)}
+	w, err := NewWorkerUDP([]string{"127.0.0.1:0"}, count, srv, packets, memoryDB.NewMemoryDb(ctx, 1), false, zerolog.Nop())
+	assert.NoError(t, err)
+	for _, s := range w.sockets {
+		defer s.conn.Close()
+	}
+	for _, q := range w.queues {
+		go w.sendWorker(ctx, q)
+	}
+
+	srv.wg.Add(packets)
+	for seq := uint32(1); seq <= packets; seq++ {
+		s := w.sockets[int(seq)%len(w.sockets)]
+		addr := &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 10000 + int(seq)%len(w.sockets)}
+		payload := binary.BigEndian.AppendUint32([]byte{7}, seq)
+		assert.NoError(t, w.handleConnection(s, addr, protocol.NewPacket(protocol.TypeData, seq, payload), nil))
+	}
+	srv.wg.Wait()
+
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	for i, seq := range srv.handled[7] {
+		if !assert.Equal(t, uint32(i+1), seq, "packets of a room arriving on different sockets are handled in order") {
+			break
+		}
+	}
+}
@@ -0,0 +1,201 @@
+package interest
+
+import (
+	"github.com/google/uuid"
+	"math"
+	"sync"
+)
+
+// Config sets the area of interest of a room. A receiver is in the area of a
+// sender when it is within Radius of it or, when Radius is zero, when its
+// cell is at most Cells cells away from the sender's on either axis. Once in
+// the area, a receiver leaves it only after moving Hysteresis further away,
+// so that users on the border do not flicker in and out.
+type Config struct {
+	CellSize   float64
+	Radius     float64
+	Cells      int
+	Hysteresis float64
+}
+
+// MaxReach bounds the number of cells a receiver can be away from a sender
+// and still be in its area.
+const MaxReach = 1 << 16
+
+// Valid reports whether the config describes an area of interest.
+func (c Config) Valid() bool {
+	finite := func(v float64) bool { return v >= 0 && !math.IsInf(v, 0) && !math.IsNaN(v) }
+	if !finite(c.CellSize) || c.CellSize == 0 || !finite(c.Radius) || !finite(c.Hysteresis) || c.Cells < 0 || c.Cells > MaxReach {
+		return false
+	}
+	return (c.Radius+c.Hysteresis)/c.CellSize <= MaxReach && float64(c.Cells)+math.Ceil(c.Hysteresis/c.CellSize) <= MaxReach
+}
+
+type Position struct {
+	X, Y float64
+}
+
+type cell struct {
+	x, y int64
+}
+
+// Grid keeps the positions of the users of a room in a uniform grid of
+// CellSize cells.
+type Grid struct {
+	mu        sync.Mutex
+	config    Config
+	positions map[uuid.UUID]Position
+	cells     map[cell]map[uuid.UUID]struct{}
+	// visible holds, for every sender, the receivers that were in its area
+	// the last time it sent.
+	visible map[uuid.UUID]map[uuid.UUID]struct{}
+}
+
+// maxCell bounds cell coordinates, so that far away positions cannot overflow
+// the neighborhood of a cell.
+const maxCell = 1 << 48
+
+func (g *Grid) cellOf(p Position) cell {
+	return cell{x: g.index(p.X), y: g.index(p.Y)}
+}
+
+func (g *Grid) index(v float64) int64 {
+	return int64(max(-maxCell, min(maxCell, math.Floor(v/g.config.CellSize))))
+}
+
+// Move sets the position of user.
+func (g *Grid) Move(user uuid.UUID, p Position) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if old, ok := g.positions[user]; ok {
+		g.removeFromCell(g.cellOf(old), user)
+	}
+	g.positions[user] = p
+
+	c := g.cellOf(p)
+	if g.cells[c] == nil {
+		g.cells[c] = make(map[uuid.UUID]struct{})
+	}
+	g.cells[c][user] = struct{}{}
+}
+
+// Remove forgets user.
+func (g *Grid) Remove(user uuid.UUID) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if old, ok := g.positions[user]; ok {
+		g.removeFromCell(g.cellOf(old), user)
+		delete(g.positions, user)
+	}
+	delete(g.visible, user)
+	for _, receivers := range g.visible {
+		delete(receivers, user)
+	}
+}
+
+func (g *Grid) removeFromCell(c cell, user uuid.UUID) {
+	delete(g.cells[c], user)
+	if len(g.cells[c]) == 0 {
+		delete(g.cells, c)
+	}
+}
+
+// Filter returns the users among receivers that receive a message of
+// sender, in their order. Users without a position are not filtered, and
+// neither is anything when sender has no position.
+func (g *Grid) Filter(sender uuid.UUID, receivers []uuid.UUID) (kept []uuid.UUID) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	from, ok := g.positions[sender]
+	if !ok {
+		return receivers
+	}
+
+	area := g.area(sender, from)
+	for _, user := range receivers {
+		_, positioned := g.positions[user]
+		_, inArea := area[user]
+		if user == sender || !positioned || inArea {
+			kept = append(kept, user)
+		}
+	}
+	return kept
+}
+
+// area returns the users in the area of sender and remembers them for the
+// hysteresis of the next call.
+func (g *Grid) area(sender uuid.UUID, from Position) map[uuid.UUID]struct{} {
+	previous := g.visible[sender]
+	area := make(map[uuid.UUID]struct{}, len(previous))
+	check := func(user uuid.UUID, to Position) {
+		if user == sender {
+			return
+		}
+		_, seen := previous[user]
+		if g.inArea(from, to, seen) {
+			area[user] = struct{}{}
+		}
+	}
+
+	r := g.reach()
+	if side := 2*r + 1; side*side > int64(len(g.positions)) {
+		for user, to := range g.positions {
+			check(user, to)
+		}
+	} else {
+		c := g.cellOf(from)
+		for x := c.x - r; x <= c.x+r; x++ {
+			for y := c.y - r; y <= c.y+r; y++ {
+				for user := range g.cells[cell{x, y}] {
+					check(user, g.positions[user])
+				}
+			}
+		}
+	}
+
+	g.visible[sender] = area
+	return area
+}
+
+// reach returns how many cells away from the sender a receiver can still be
+// in its area.
+func (g *Grid) reach() int64 {
+	if g.config.Radius > 0 {
+		return int64(math.Ceil((g.config.Radius + g.config.Hysteresis) / g.config.CellSize))
+	}
+	return int64(g.config.Cells) + int64(math.Ceil(g.config.Hysteresis/g.config.CellSize))
+}
+
+// inArea reports whether a receiver at to is in the area of a sender at from.
+// A receiver that was already in the area stays there until it is more than
+// Hysteresis outside of it.
+func (g *Grid) inArea(from, to Position, seen bool) bool {
+	margin := 0.0
+	if seen {
+		margin = g.config.Hysteresis
+	}
+
+	if g.config.Radius > 0 {
+		dx, dy := from.X-to.X, from.Y-to.Y
+		limit := g.config.Radius + margin
+		return dx*dx+dy*dy <= limit*limit
+	}
+
+	near := func(from, to float64) bool {
+		c, low, high := g.index(from), g.index(to-margin), g.index(to+margin)
+		return high >= c-int64(g.config.Cells) && low <= c+int64(g.config.Cells)
+	}
+	return near(from.X, to.X) && near(from.Y, to.Y)
+}
+
+func NewGrid(config Config) *Grid {
+	return &Grid{
+		config:    config,
+		positions: make(map[uuid.UUID]Position),
+		cells:     make(map[cell]map[uuid.UUID]struct{}),
+		visible:   make(map[uuid.UUID]map[uuid.UUID]struct{}),
+	}
+}
@@ -0,0 +1,79 @@
+package interest
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestRadius(t *testing.T) {
+	sender, near, far, unknown := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	all := []uuid.UUID{sender, near, far, unknown}
+
+	grid := NewGrid(Config{CellSize: 10, Radius: 15, Hysteresis: 5})
+	grid.Move(sender, Position{0, 0})
+	grid.Move(near, Position{9, 9})
+	grid.Move(far, Position{18, 0})
+
+	assert.Equal(t, []uuid.UUID{sender, near, unknown}, grid.Filter(sender, all), "users without a position are not filtered")
+	assert.Equal(t, all, grid.Filter(unknown, all), "senders without a position are not filtered")
+
+	// near stays in the area until it is more than Radius+Hysteresis away.
+	grid.Move(near, Position{19, 0})
+	assert.Equal(t, []uuid.UUID{sender, near, unknown}, grid.Filter(sender, all))
+	grid.Move(near, Position{21, 0})
+	assert.Equal(t, []uuid.UUID{sender, unknown}, grid.Filter(sender, all))
+	grid.Move(near, Position{19, 0})
+	assert.Equal(t, []uuid.UUID{sender, unknown}, grid.Filter(sender, all), "coming back needs Radius again")
+
+	grid.Remove(sender)
+	assert.Equal(t, all, grid.Filter(sender, all))
+}
+
+func TestCells(t *testing.T) {
+	sender, neighbor, distant := uuid.New(), uuid.New(), uuid.New()
+	all := []uuid.UUID{sender, neighbor, distant}
+
+	grid := NewGrid(Config{CellSize: 10, Cells: 1, Hysteresis: 3})
+	grid.Move(sender, Position{5, 5})
+	grid.Move(neighbor, Position{-5, 15})
+	grid.Move(distant, Position{25, 5})
+	assert.Equal(t, []uuid.UUID{sender, neighbor}, grid.Filter(sender, all))
+
+	grid.Move(neighbor, Position{-12, 15})
+	assert.Equal(t, []uuid.UUID{sender, neighbor}, grid.Filter(sender, all), "within Hysteresis of the neighborhood")
+	grid.Move(neighbor, Position{-20, 15})
+	assert.Equal(t, []uuid.UUID{sender}, grid.Filter(sender, all))
+}
+
+func TestGridScansCellsOrUsers(t *testing.T) {
+	sender := uuid.New()
+	config := Config{CellSize: 1, Radius: 2}
+
+	// With few users the grid checks every user, with many it scans the
+	// cells around the sender; both give the same area.
+	for _, count := range []int{3, 100} {
+		grid := NewGrid(config)
+		grid.Move(sender, Position{0, 0})
+		var users, expected []uuid.UUID
+		for i := 0; i < count; i++ {
+			user := uuid.New()
+			x := float64(i%10) - 5
+			y := float64(i/10) - 5
+			grid.Move(user, Position{x, y})
+			users = append(users, user)
+			if x*x+y*y <= 4 {
+				expected = append(expected, user)
+			}
+		}
+		assert.Equal(t, expected, grid.Filter(sender, users))
+	}
+}
+
+func TestConfigValid(t *testing.T) {
+	assert.True(t, Config{CellSize: 10, Radius: 20, Hysteresis: 2}.Valid())
+	assert.True(t, Config{CellSize: 10, Cells: 2}.Valid())
+	assert.False(t, Config{Radius: 20}.Valid())
+	assert.False(t, Config{CellSize: 10, Radius: -1}.Valid())
+	assert.False(t, Config{CellSize: 1e-9, Radius: 1}.Valid())
+}
@@ -0,0 +1,106 @@
+package link
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	PongTimeout = time.Second * 3
+)
+
+// Stats are the link quality estimates of one session.
+type Stats struct {
+	RTT    time.Duration
+	Jitter time.Duration
+	Loss   float64
+	Pings  uint64
+	Pongs  uint64
+}
+
+// Link measures a session's connection from the pings the server sends and
+// the pongs the client returns. RTT is smoothed as in RFC 6298, jitter is the
+// RFC 3550 interarrival estimate, and loss is the moving share of pings left
+// unanswered for PongTimeout.
+type Link struct {
+	mu       sync.Mutex
+	nextID   uint32
+	pending  map[uint32]time.Time
+	lastPing time.Time
+	lastRTT  time.Duration
+	stats    Stats
+}
+
+// Ping registers a new ping and returns its ID, unless the previous ping was
+// sent less than interval ago.
+func (l *Link) Ping(now time.Time, interval time.Duration) (id uint32, ok bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.expire(now)
+	if now.Sub(l.lastPing) < interval {
+		return 0, false
+	}
+
+	l.nextID++
+	l.pending[l.nextID] = now
+	l.lastPing = now
+	l.stats.Pings++
+
+	return l.nextID, true
+}
+
+// Pong records the answer to a ping. Unknown and expired IDs are ignored.
+func (l *Link) Pong(id uint32, now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	sentAt, ok := l.pending[id]
+	if !ok {
+		return false
+	}
+	delete(l.pending, id)
+
+	rtt := now.Sub(sentAt)
+	if l.stats.Pongs == 0 {
+		l.stats.RTT = rtt
+	} else {
+		l.stats.RTT += (rtt - l.stats.RTT) / 8
+		l.stats.Jitter += (abs(rtt-l.lastRTT) - l.stats.Jitter) / 16
+	}
+	l.lastRTT = rtt
+	l.stats.Pongs++
+	l.stats.Loss -= l.stats.Loss / 8
+
+	return true
+}
+
+func (l *Link) Stats() Stats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.expire(time.Now())
+	return l.stats
+}
+
+func (l *Link) expire(now time.Time) {
+	for id, sentAt := range l.pending {
+		if now.Sub(sentAt) > PongTimeout {
+			delete(l.pending, id)
+			l.stats.Loss += (1 - l.stats.Loss) / 8
+		}
+	}
+}
+
+func abs(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
+
+func NewLink() *Link {
+	return &Link{
+		pending: make(map[uint32]time.Time),
+	}
+}
@@ -0,0 +1,50 @@
+package link
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestRTTAndJitter(t *testing.T) {
+	l := NewLink()
+	now := time.Now()
+
+	for i, rtt := range []time.Duration{100, 120, 80} {
+		sentAt := now.Add(time.Duration(i) * time.Second)
+		id, ok := l.Ping(sentAt, time.Second)
+		assert.True(t, ok)
+		assert.True(t, l.Pong(id, sentAt.Add(rtt*time.Millisecond)))
+		assert.False(t, l.Pong(id, sentAt.Add(rtt*time.Millisecond)))
+	}
+
+	stats := l.Stats()
+	assert.Equal(t, uint64(3), stats.Pings)
+	assert.Equal(t, uint64(3), stats.Pongs)
+	assert.InDelta(t, float64(99687500*time.Nanosecond), float64(stats.RTT), float64(time.Microsecond))
+	assert.Greater(t, stats.Jitter, time.Duration(0))
+	assert.Zero(t, stats.Loss)
+}
+
+func TestPingInterval(t *testing.T) {
+	l := NewLink()
+	now := time.Now()
+
+	_, ok := l.Ping(now, time.Second)
+	assert.True(t, ok)
+	_, ok = l.Ping(now.Add(time.Second/2), time.Second)
+	assert.False(t, ok)
+	_, ok = l.Ping(now.Add(time.Second), time.Second)
+	assert.True(t, ok)
+}
+
+func TestLoss(t *testing.T) {
+	l := NewLink()
+	now := time.Now()
+
+	id, _ := l.Ping(now, 0)
+	l.Ping(now.Add(PongTimeout+time.Second), 0)
+
+	assert.Equal(t, 0.125, l.Stats().Loss)
+	assert.False(t, l.Pong(id, now.Add(PongTimeout+time.Second)))
+}
@@ -0,0 +1,166 @@
+package lockstep
+
+import (
+	"bytes"
+	"github.com/ascenmmo/udp-server/pkg/errors"
+	"github.com/ascenmmo/udp-server/pkg/protocol"
+	"github.com/google/uuid"
+	"slices"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultTimeout = time.Millisecond * 200
+	History        = 1024
+)
+
+// Frame is a closed lockstep frame with its encoded frame packet payload.
+type Frame struct {
+	Number  uint32
+	Payload []byte
+}
+
+type pending struct {
+	inputs map[uuid.UUID][]byte
+}
+
+// Buffer collects the inputs of a lockstep room. An input sampled on frame F
+// is played on frame F+delay. Frames close in order, once every member has
+// reported its input or timeout after the previous frame closed or the first
+// input arrived; members without input are then marked missing. The last
+// History frames are kept to be sent again.
+type Buffer struct {
+	mu       sync.Mutex
+	delay    uint32
+	timeout  time.Duration
+	started  bool
+	next     uint32
+	deadline time.Time
+	pending  map[uint32]*pending
+	history  []Frame
+}
+
+// Add records the input of user sampled on frame.
+func (b *Buffer) Add(user uuid.UUID, frame uint32, input []byte, now time.Time) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	frame += b.delay
+	if !b.started {
+		b.started, b.next = true, frame
+	}
+	if int32(frame-b.next) < 0 {
+		return errors.ErrFrameLate
+	}
+	if frame-b.next >= History {
+		return errors.ErrFrameTooFar
+	}
+
+	p, ok := b.pending[frame]
+	if !ok {
+		p = &pending{inputs: make(map[uuid.UUID][]byte)}
+		b.pending[frame] = p
+	}
+	if _, ok := p.inputs[user]; !ok {
+		p.inputs[user] = append([]byte(nil), input...)
+	}
+	if b.deadline.IsZero() {
+		b.deadline = now.Add(b.timeout)
+	}
+
+	return nil
+}
+
+// Close closes the frames that are complete for members or whose deadline
+// has passed at now, and returns them in order.
+func (b *Buffer) Close(members []uuid.UUID, now time.Time) (frames []Frame) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.started {
+		return nil
+	}
+
+	members = slices.Clone(members)
+	slices.SortFunc(members, func(a, b uuid.UUID) int {
+		return bytes.Compare(a[:], b[:])
+	})
+
+	for len(b.pending) > 0 {
+		p := b.pending[b.next]
+		if !p.complete(members) && now.Before(b.deadline) {
+			break
+		}
+
+		frame := Frame{Number: b.next, Payload: protocol.FramePayload(b.next, p.frameInputs(members))}
+		b.history[b.next%History] = frame
+		frames = append(frames, frame)
+
+		delete(b.pending, b.next)
+		b.next++
+		b.deadline = now.Add(b.timeout)
+	}
+	if len(b.pending) == 0 {
+		b.deadline = time.Time{}
+	}
+
+	return frames
+}
+
+// Frames returns the closed frames from from to to that are still kept.
+func (b *Buffer) Frames(from, to uint32) (frames []Frame) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if int32(b.next-from) > History {
+		from = b.next - History
+	}
+	for n := from; int32(to-n) >= 0 && int32(b.next-n) > 0; n++ {
+		frame := b.history[n%History]
+		if frame.Payload != nil && frame.Number == n {
+			frames = append(frames, frame)
+		}
+		if n == to {
+			break
+		}
+	}
+	return frames
+}
+
+func (p *pending) complete(members []uuid.UUID) bool {
+	if p == nil {
+		return false
+	}
+	for _, member := range members {
+		if _, ok := p.inputs[member]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func (p *pending) frameInputs(members []uuid.UUID) []protocol.FrameInput {
+	inputs := make([]protocol.FrameInput, 0, len(members))
+	for _, member := range members {
+		var input []byte
+		var ok bool
+		if p != nil {
+			input, ok = p.inputs[member]
+		}
+		inputs = append(inputs, protocol.FrameInput{UserID: member, Input: input, Missing: !ok})
+	}
+	return inputs
+}
+
+func NewBuffer(delay uint32, timeout time.Duration) *Buffer {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	return &Buffer{
+		delay:   delay,
+		timeout: timeout,
+		pending: make(map[uint32]*pending),
+		history: make([]Frame, History),
+	}
+}
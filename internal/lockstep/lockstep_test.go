@@ -0,0 +1,85 @@
+package lockstep
+
+import (
+	"github.com/ascenmmo/udp-server/pkg/errors"
+	"github.com/ascenmmo/udp-server/pkg/protocol"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func parse(t *testing.T, frame Frame) map[uuid.UUID]protocol.FrameInput {
+	number, inputs, err := protocol.ParseFrame(frame.Payload)
+	assert.NoError(t, err)
+	assert.Equal(t, frame.Number, number)
+
+	byUser := make(map[uuid.UUID]protocol.FrameInput)
+	for _, in := range inputs {
+		byUser[in.UserID] = in
+	}
+	return byUser
+}
+
+func TestFrameClosesWhenEveryMemberReported(t *testing.T) {
+	a, b := uuid.New(), uuid.New()
+	members := []uuid.UUID{a, b}
+	buf := NewBuffer(2, time.Second)
+	now := time.Now()
+
+	assert.NoError(t, buf.Add(a, 10, []byte("a10"), now))
+	assert.Empty(t, buf.Close(members, now))
+
+	assert.NoError(t, buf.Add(b, 10, []byte("b10"), now))
+	frames := buf.Close(members, now)
+	assert.Len(t, frames, 1)
+	assert.Equal(t, uint32(12), frames[0].Number, "inputs are played after the input delay")
+	inputs := parse(t, frames[0])
+	assert.Equal(t, []byte("a10"), inputs[a].Input)
+	assert.Equal(t, []byte("b10"), inputs[b].Input)
+
+	assert.ErrorIs(t, buf.Add(a, 10, nil, now), errors.ErrFrameLate)
+	assert.ErrorIs(t, buf.Add(a, 11+History, nil, now), errors.ErrFrameTooFar)
+}
+
+func TestFrameClosesAtDeadline(t *testing.T) {
+	a, b := uuid.New(), uuid.New()
+	members := []uuid.UUID{a, b}
+	buf := NewBuffer(0, time.Millisecond*100)
+	now := time.Now()
+
+	assert.NoError(t, buf.Add(a, 1, []byte("a1"), now))
+	assert.NoError(t, buf.Add(a, 2, []byte("a2"), now))
+	assert.NoError(t, buf.Add(b, 2, []byte("b2"), now))
+	assert.Empty(t, buf.Close(members, now.Add(time.Millisecond*99)))
+
+	// Frame 1 times out with b missing, then frame 2 is already complete.
+	frames := buf.Close(members, now.Add(time.Millisecond*100))
+	assert.Len(t, frames, 2)
+	inputs := parse(t, frames[0])
+	assert.False(t, inputs[a].Missing)
+	assert.True(t, inputs[b].Missing)
+	inputs = parse(t, frames[1])
+	assert.False(t, inputs[b].Missing)
+}
+
+func TestFramesAreKeptForResend(t *testing.T) {
+	a := uuid.New()
+	buf := NewBuffer(0, time.Second)
+	now := time.Now()
+
+	for frame := uint32(1); frame <= History+10; frame++ {
+		assert.NoError(t, buf.Add(a, frame, []byte{byte(frame)}, now))
+		assert.Len(t, buf.Close([]uuid.UUID{a}, now), 1)
+	}
+
+	frames := buf.Frames(5, 7)
+	assert.Empty(t, frames, "frames older than History are gone")
+
+	frames = buf.Frames(History+8, History+20)
+	assert.Len(t, frames, 3)
+	for i, frame := range frames {
+		assert.Equal(t, uint32(History+8+i), frame.Number)
+		assert.Equal(t, []byte{byte(frame.Number)}, parse(t, frame)[a].Input)
+	}
+}
@@ -0,0 +1,171 @@
+package reliable
+
+import (
+	"github.com/ascenmmo/udp-server/pkg/protocol"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	RetransmitTimeout    = time.Millisecond * 200
+	MaxRetransmitTimeout = time.Second * 2
+	MaxRetransmits       = 8
+	ReceiveWindow        = 256
+)
+
+// Stats are the reliable channel counters of one room.
+type Stats struct {
+	Sent        atomic.Uint64
+	Retransmits atomic.Uint64
+	Acks        atomic.Uint64
+	Duplicates  atomic.Uint64
+	Dropped     atomic.Uint64
+}
+
+type outbound struct {
+	packet   protocol.Packet
+	attempts int
+	timer    *time.Timer
+}
+
+// Channel is the reliable, ordered channel of one session. Inbound packets
+// are acknowledged, deduplicated and released in sequence order; outbound
+// packets are numbered and retransmitted until the client acknowledges them.
+type Channel struct {
+	mu    sync.Mutex
+	stats *Stats
+
+	nextRecv uint32
+	pending  map[uint32]protocol.Packet
+
+	nextSend uint32
+	unacked  map[uint32]*outbound
+	closed   bool
+}
+
+// Receive accepts an inbound reliable packet and returns the packets that
+// are now deliverable in order. Duplicates return nothing.
+func (c *Channel) Receive(packet protocol.Packet) (deliver []protocol.Packet) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	seq := packet.Reliable
+	switch {
+	case seq < c.nextRecv:
+		c.stats.Duplicates.Add(1)
+		return nil
+	case seq-c.nextRecv >= ReceiveWindow:
+		c.stats.Dropped.Add(1)
+		return nil
+	case seq > c.nextRecv:
+		if _, ok := c.pending[seq]; ok {
+			c.stats.Duplicates.Add(1)
+			return nil
+		}
+		packet.Payload = append([]byte(nil), packet.Payload...)
+		c.pending[seq] = packet
+		return nil
+	}
+
+	deliver = append(deliver, packet)
+	c.nextRecv++
+	for {
+		next, ok := c.pending[c.nextRecv]
+		if !ok {
+			break
+		}
+		delete(c.pending, c.nextRecv)
+		deliver = append(deliver, next)
+		c.nextRecv++
+	}
+
+	return deliver
+}
+
+// Send numbers and encodes an outbound packet and returns the datagram for
+// the caller to write. Until Ack is called with its sequence number, the
+// packet is encoded again and retransmitted through retransmit, so that an
+// encrypted retransmission gets a fresh sequence number and nonce. The
+// payload is copied, since the caller's buffer may be reused once the first
+// datagram is written. A closed channel returns nil.
+func (c *Channel) Send(packet protocol.Packet, encode func(protocol.Packet) []byte, retransmit func([]byte) error) (buf []byte) {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return nil
+	}
+	packet.Header.Flags |= protocol.FlagReliable
+	packet.Reliable = c.nextSend
+	c.nextSend++
+	packet.Payload = append([]byte(nil), packet.Payload...)
+
+	out := &outbound{packet: packet}
+	c.unacked[packet.Reliable] = out
+	out.timer = time.AfterFunc(RetransmitTimeout, func() {
+		c.retransmit(packet.Reliable, encode, retransmit)
+	})
+	c.mu.Unlock()
+
+	c.stats.Sent.Add(1)
+
+	return encode(packet)
+}
+
+func (c *Channel) Ack(sequences ...uint32) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, seq := range sequences {
+		out, ok := c.unacked[seq]
+		if !ok {
+			continue
+		}
+		out.timer.Stop()
+		delete(c.unacked, seq)
+		c.stats.Acks.Add(1)
+	}
+}
+
+// Close stops all pending retransmissions.
+func (c *Channel) Close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for seq, out := range c.unacked {
+		out.timer.Stop()
+		delete(c.unacked, seq)
+	}
+	c.closed = true
+}
+
+func (c *Channel) retransmit(seq uint32, encode func(protocol.Packet) []byte, write func([]byte) error) {
+	c.mu.Lock()
+	out, ok := c.unacked[seq]
+	if !ok || c.closed {
+		c.mu.Unlock()
+		return
+	}
+	out.attempts++
+	if out.attempts > MaxRetransmits {
+		delete(c.unacked, seq)
+		c.mu.Unlock()
+		c.stats.Dropped.Add(1)
+		return
+	}
+	out.timer.Reset(min(RetransmitTimeout<<out.attempts, MaxRetransmitTimeout))
+	c.mu.Unlock()
+
+	c.stats.Retransmits.Add(1)
+	_ = write(encode(out.packet))
+}
+
+func NewChannel(stats *Stats) *Channel {
+	return &Channel{
+		stats:    stats,
+		nextRecv: 1,
+		pending:  make(map[uint32]protocol.Packet),
+		nextSend: 1,
+		unacked:  make(map[uint32]*outbound),
+	}
+}
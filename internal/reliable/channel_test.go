@@ -0,0 +1,86 @@
+package reliable
+
+import (
+	"github.com/ascenmmo/udp-server/pkg/protocol"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+	"time"
+)
+
+func reliablePacket(seq uint32, payload string) protocol.Packet {
+	packet := protocol.NewPacket(protocol.TypeData, seq, []byte(payload))
+	packet.Header.Flags |= protocol.FlagReliable
+	packet.Reliable = seq
+	return packet
+}
+
+func TestReceiveInOrder(t *testing.T) {
+	stats := &Stats{}
+	c := NewChannel(stats)
+
+	assert.Empty(t, c.Receive(reliablePacket(2, "b")))
+	assert.Empty(t, c.Receive(reliablePacket(3, "c")))
+	assert.Empty(t, c.Receive(reliablePacket(3, "c")))
+
+	delivered := c.Receive(reliablePacket(1, "a"))
+	assert.Len(t, delivered, 3)
+	for i, payload := range []string{"a", "b", "c"} {
+		assert.Equal(t, payload, string(delivered[i].Payload))
+	}
+
+	assert.Empty(t, c.Receive(reliablePacket(2, "b")))
+	assert.Equal(t, uint64(2), stats.Duplicates.Load())
+}
+
+func TestRetransmitUntilAck(t *testing.T) {
+	stats := &Stats{}
+	c := NewChannel(stats)
+
+	var mu sync.Mutex
+	var writes [][]byte
+	write := func(buf []byte) error {
+		mu.Lock()
+		defer mu.Unlock()
+		writes = append(writes, buf)
+		return nil
+	}
+
+	buf := c.Send(protocol.NewPacket(protocol.TypeData, 0, []byte("event")), protocol.Packet.Encode, write)
+	assert.NoError(t, write(buf))
+
+	time.Sleep(RetransmitTimeout + RetransmitTimeout/2)
+	c.Ack(1)
+	time.Sleep(RetransmitTimeout * 3)
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Len(t, writes, 2)
+	packet, err := protocol.Decode(writes[1])
+	assert.NoError(t, err)
+	assert.True(t, packet.IsReliable())
+	assert.Equal(t, uint32(1), packet.Reliable)
+	assert.Equal(t, uint64(1), stats.Retransmits.Load())
+	assert.Equal(t, uint64(1), stats.Acks.Load())
+}
+
+func TestRetransmitAfterBufferReuse(t *testing.T) {
+	c := NewChannel(&Stats{})
+	defer c.Close()
+
+	var writes [][]byte
+	write := func(buf []byte) error {
+		writes = append(writes, buf)
+		return nil
+	}
+
+	payload := []byte("ORIGINAL")
+	c.Send(protocol.NewPacket(protocol.TypeData, 0, payload), protocol.Packet.Encode, write)
+	copy(payload, "XXXXXXXX")
+	c.retransmit(1, protocol.Packet.Encode, write)
+
+	assert.Len(t, writes, 1)
+	packet, err := protocol.Decode(writes[0])
+	assert.NoError(t, err)
+	assert.Equal(t, "ORIGINAL", string(packet.Payload))
+}
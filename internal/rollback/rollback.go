@@ -0,0 +1,150 @@
+package rollback
+
+import (
+	"github.com/ascenmmo/udp-server/pkg/errors"
+	"github.com/ascenmmo/udp-server/pkg/protocol"
+	"github.com/google/uuid"
+	"sync"
+)
+
+const (
+	DefaultHistory = 64
+	MaxHistory     = 1024
+)
+
+// ring keeps the inputs of one player for its last frames.
+type ring struct {
+	frames []uint32
+	inputs [][]byte
+	latest uint32
+}
+
+// set keeps the input of frame. Frames older than the ring are ignored.
+func (r *ring) set(frame uint32, input []byte) {
+	size := uint32(len(r.frames))
+	if frame == 0 || r.latest >= size && frame <= r.latest-size {
+		return
+	}
+
+	i := frame % size
+	if r.frames[i] == frame {
+		return
+	}
+	r.frames[i] = frame
+	r.inputs[i] = append([]byte(nil), input...)
+	if frame > r.latest {
+		r.latest = frame
+	}
+}
+
+func (r *ring) get(frame uint32) (input []byte, ok bool) {
+	i := frame % uint32(len(r.frames))
+	if frame == 0 || r.frames[i] != frame {
+		return nil, false
+	}
+	return r.inputs[i], true
+}
+
+type player struct {
+	ring      ring
+	confirmed uint32
+}
+
+// Buffer keeps the last frames of inputs of every player of a rollback room,
+// and the last frame each player has every other player's input for. Frames
+// start at 1; a confirmed frame of 0 means that the player has no input yet.
+type Buffer struct {
+	mu      sync.Mutex
+	history int
+	players map[uuid.UUID]*player
+}
+
+// Add records inputs of user for the frames starting with first, and the last
+// frame user has confirmed. The first inputs of a player may start at any
+// frame; later ones may not start more than the history ahead of the latest
+// frame of the player, or nothing is recorded. Frames past the largest frame
+// number are ignored.
+func (b *Buffer) Add(user uuid.UUID, confirmed, first uint32, inputs [][]byte) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	p := b.player(user)
+	if latest := p.ring.latest; len(inputs) > 0 && latest > 0 && first > latest && first-latest > uint32(b.history) {
+		return errors.ErrFrameTooFar
+	}
+	if confirmed > p.confirmed {
+		p.confirmed = confirmed
+	}
+	for i, input := range inputs {
+		frame := first + uint32(i)
+		if frame < first {
+			break
+		}
+		p.ring.set(frame, input)
+	}
+	return nil
+}
+
+// Missing returns, for every one of others, the inputs kept after the frame
+// user has confirmed. Each run stops at the first frame that is not kept.
+func (b *Buffer) Missing(user uuid.UUID, others []uuid.UUID) (players []protocol.PlayerInputs) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	confirmed := b.player(user).confirmed
+	for _, other := range others {
+		p, ok := b.players[other]
+		if !ok || other == user {
+			continue
+		}
+
+		latest := p.ring.latest
+		if confirmed >= latest {
+			continue
+		}
+
+		first := max(confirmed, latest-min(latest, uint32(b.history))) + 1
+		var inputs [][]byte
+		for i := uint32(0); i <= latest-first; i++ {
+			input, ok := p.ring.get(first + i)
+			if !ok {
+				break
+			}
+			inputs = append(inputs, input)
+		}
+		if len(inputs) > 0 {
+			players = append(players, protocol.PlayerInputs{UserID: other, First: first, Inputs: inputs})
+		}
+	}
+
+	return players
+}
+
+// Remove forgets the inputs of a player that left the room.
+func (b *Buffer) Remove(user uuid.UUID) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.players, user)
+}
+
+func (b *Buffer) player(user uuid.UUID) *player {
+	p, ok := b.players[user]
+	if !ok {
+		p = &player{ring: ring{
+			frames: make([]uint32, b.history),
+			inputs: make([][]byte, b.history),
+		}}
+		b.players[user] = p
+	}
+	return p
+}
+
+func NewBuffer(history int) *Buffer {
+	if history <= 0 {
+		history = DefaultHistory
+	}
+	return &Buffer{
+		history: history,
+		players: make(map[uuid.UUID]*player),
+	}
+}
@@ -0,0 +1,98 @@
+package rollback
+
+import (
+	"github.com/ascenmmo/udp-server/pkg/errors"
+	"github.com/ascenmmo/udp-server/pkg/protocol"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"math"
+	"testing"
+)
+
+func inputs(from, to int) (in [][]byte) {
+	for frame := from; frame <= to; frame++ {
+		in = append(in, []byte{byte(frame)})
+	}
+	return in
+}
+
+func TestMissingInputsFollowConfirmedFrame(t *testing.T) {
+	a, b, c := uuid.New(), uuid.New(), uuid.New()
+	buf := NewBuffer(8)
+
+	// a sends frames 1..3, then 2..5 again as redundancy.
+	assert.NoError(t, buf.Add(a, 0, 1, inputs(1, 3)))
+	assert.NoError(t, buf.Add(a, 0, 2, inputs(2, 5)))
+	assert.NoError(t, buf.Add(c, 0, 1, inputs(1, 2)))
+
+	assert.Equal(t, []protocol.PlayerInputs{
+		{UserID: a, First: 1, Inputs: inputs(1, 5)},
+		{UserID: c, First: 1, Inputs: inputs(1, 2)},
+	}, buf.Missing(b, []uuid.UUID{a, b, c}))
+
+	// b confirms frame 3: it only misses a's frames 4 and 5.
+	assert.NoError(t, buf.Add(b, 3, 1, nil))
+	assert.Equal(t, []protocol.PlayerInputs{
+		{UserID: a, First: 4, Inputs: inputs(4, 5)},
+	}, buf.Missing(b, []uuid.UUID{a, b, c}))
+
+	// A confirmed frame never goes back.
+	assert.NoError(t, buf.Add(b, 1, 1, nil))
+	assert.Len(t, buf.Missing(b, []uuid.UUID{a}), 1)
+	assert.Equal(t, uint32(4), buf.Missing(b, []uuid.UUID{a})[0].First)
+}
+
+func TestRingKeepsLastFrames(t *testing.T) {
+	a, b := uuid.New(), uuid.New()
+	buf := NewBuffer(4)
+
+	assert.NoError(t, buf.Add(a, 0, 1, inputs(1, 10)))
+	missing := buf.Missing(b, []uuid.UUID{a})
+	assert.Equal(t, []protocol.PlayerInputs{{UserID: a, First: 7, Inputs: inputs(7, 10)}}, missing)
+
+	// Frames older than the ring are ignored.
+	assert.NoError(t, buf.Add(a, 0, 2, [][]byte{{99}}))
+	assert.Equal(t, missing, buf.Missing(b, []uuid.UUID{a}))
+
+	// A gap ends the run.
+	assert.NoError(t, buf.Add(a, 0, 12, inputs(12, 12)))
+	assert.Equal(t, []protocol.PlayerInputs{{UserID: a, First: 9, Inputs: inputs(9, 10)}}, buf.Missing(b, []uuid.UUID{a}))
+
+	buf.Remove(a)
+	assert.Empty(t, buf.Missing(b, []uuid.UUID{a}))
+}
+
+func TestFramesNearTheLargestFrameNumber(t *testing.T) {
+	a, b := uuid.New(), uuid.New()
+	buf := NewBuffer(4)
+
+	assert.NoError(t, buf.Add(a, 0, math.MaxUint32-5, inputs(0, 5)))
+	assert.Equal(t, []protocol.PlayerInputs{
+		{UserID: a, First: math.MaxUint32 - 3, Inputs: inputs(2, 5)},
+	}, buf.Missing(b, []uuid.UUID{a}), "the run ends at the largest frame without wrapping")
+
+	// Frames that would wrap past the largest one are ignored, and old ones
+	// still are.
+	assert.NoError(t, buf.Add(a, 0, math.MaxUint32-1, [][]byte{{4}, {5}, {6}, {7}}))
+	assert.NoError(t, buf.Add(a, 0, math.MaxUint32-4, [][]byte{{99}}))
+	assert.Equal(t, []protocol.PlayerInputs{
+		{UserID: a, First: math.MaxUint32 - 3, Inputs: inputs(2, 5)},
+	}, buf.Missing(b, []uuid.UUID{a}))
+
+	assert.NoError(t, buf.Add(b, math.MaxUint32, 1, nil))
+	assert.Empty(t, buf.Missing(b, []uuid.UUID{a}))
+}
+
+func TestFramesTooFarAhead(t *testing.T) {
+	a, b := uuid.New(), uuid.New()
+	buf := NewBuffer(4)
+
+	assert.NoError(t, buf.Add(a, 0, 100, inputs(100, 101)), "a player may start at any frame")
+	assert.Equal(t, errors.ErrFrameTooFar, buf.Add(a, 0, math.MaxUint32, inputs(1, 1)))
+	assert.Equal(t, errors.ErrFrameTooFar, buf.Add(a, 0, 106, inputs(106, 106)))
+	assert.NoError(t, buf.Add(a, 0, 105, inputs(105, 105)))
+	assert.NoError(t, buf.Add(a, 0, 102, inputs(102, 104)))
+	assert.Equal(t, []protocol.PlayerInputs{
+		{UserID: a, First: 102, Inputs: inputs(102, 105)},
+	}, buf.Missing(b, []uuid.UUID{a}))
+}
@@ -0,0 +1,128 @@
+package service
+
+import (
+	"github.com/ascenmmo/udp-server/internal/session"
+	"github.com/ascenmmo/udp-server/pkg/api/types"
+	"github.com/ascenmmo/udp-server/pkg/protocol"
+	"github.com/google/uuid"
+)
+
+// group applies a group join or leave sent by a member of the room.
+func (s *service) group(messages []types.Message, sess *session.Session, room *types.Room, packet protocol.Packet) ([]types.Message, error) {
+	if packet.IsFragment() {
+		full, complete, err := sess.Fragments.Add(packet)
+		if err != nil || !complete {
+			return messages, err
+		}
+		packet.Payload = full
+	}
+
+	name, err := protocol.ParseGroup(packet.Payload)
+	if err != nil {
+		return messages, err
+	}
+
+	if packet.Header.Type == protocol.TypeGroupLeave {
+		return s.leaveGroup(messages, room, name, sess.Info.UserID), nil
+	}
+	return s.joinGroup(messages, room, name, sess.Info.UserID)
+}
+
+func (s *service) joinGroup(messages []types.Message, room *types.Room, name string, userID uuid.UUID) ([]types.Message, error) {
+	joined, err := room.JoinGroup(name, userID)
+	if err != nil || !joined {
+		return messages, err
+	}
+	return s.appendGroupEvent(messages, room, protocol.TypeGroupJoined, name, userID), nil
+}
+
+func (s *service) leaveGroup(messages []types.Message, room *types.Room, name string, userID uuid.UUID) []types.Message {
+	if !room.LeaveGroup(name, userID) {
+		return messages
+	}
+	return s.appendGroupEvent(messages, room, protocol.TypeGroupLeft, name, userID)
+}
+
+// appendGroupEvent tells every member of the room, the user included, that a
+// user joined or left a group. The notification is reliable; legacy clients
+// do not receive it.
+func (s *service) appendGroupEvent(messages []types.Message, room *types.Room, eventType protocol.MessageType, name string, userID uuid.UUID) []types.Message {
+	var users []types.User
+	for _, user := range room.GetUser() {
+		if !user.Legacy {
+			users = append(users, *user)
+		}
+	}
+	if len(users) == 0 {
+		return messages
+	}
+
+	event := protocol.NewPacket(eventType, 0, protocol.GroupEventPayload(name, userID))
+	event.Header.Flags |= protocol.FlagReliable
+
+	return append(messages, types.Message{Users: users, Packet: event})
+}
+
+// appendGroups sends a user that just joined the room a group joined event
+// for every group, listing all of its members.
+func (s *service) appendGroups(messages []types.Message, room *types.Room, user types.User) []types.Message {
+	if user.Legacy {
+		return messages
+	}
+	for _, group := range room.Groups() {
+		event := protocol.NewPacket(protocol.TypeGroupJoined, 0, protocol.GroupEventPayload(group.Name, group.Users...))
+		event.Header.Flags |= protocol.FlagReliable
+		messages = append(messages, types.Message{Users: []types.User{user}, Packet: event})
+	}
+	return messages
+}
+
+func (s *service) JoinGroup(token string, name string) (err error) {
+	info, err := s.token.ParseToken(token)
+	if err != nil {
+		return err
+	}
+	if _, err = protocol.ParseGroup([]byte(name)); err != nil {
+		return err
+	}
+
+	room, err := s.getRoomByClientInfo(info)
+	if err != nil {
+		return err
+	}
+
+	messages, err := s.joinGroup(nil, room, name, info.UserID)
+	s.send(messages)
+
+	return err
+}
+
+func (s *service) LeaveGroup(token string, name string) (err error) {
+	info, err := s.token.ParseToken(token)
+	if err != nil {
+		return err
+	}
+
+	room, err := s.getRoomByClientInfo(info)
+	if err != nil {
+		return err
+	}
+
+	s.send(s.leaveGroup(nil, room, name, info.UserID))
+
+	return nil
+}
+
+func (s *service) GetGroups(token string) (groups []types.Group, err error) {
+	info, err := s.token.ParseToken(token)
+	if err != nil {
+		return nil, err
+	}
+
+	room, err := s.getRoomByClientInfo(info)
+	if err != nil {
+		return nil, err
+	}
+
+	return room.Groups(), nil
+}
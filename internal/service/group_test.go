@@ -0,0 +1,80 @@
+package service
+
+import (
+	"github.com/ascenmmo/udp-server/pkg/api/types"
+	"github.com/ascenmmo/udp-server/pkg/errors"
+	"github.com/ascenmmo/udp-server/pkg/protocol"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func groupEvents(t *testing.T, messages []types.Message, eventType protocol.MessageType) (events []types.Group) {
+	for _, msg := range messages {
+		if msg.Packet.Header.Type != eventType {
+			continue
+		}
+		assert.True(t, msg.Packet.IsReliable())
+		name, users, err := protocol.ParseGroupEvent(msg.Packet.Payload)
+		assert.NoError(t, err)
+		events = append(events, types.Group{Name: name, Users: users})
+	}
+	return events
+}
+
+func TestGroups(t *testing.T) {
+	room := newTestRoom(t, 1200)
+	assert.NoError(t, room.service.CreateRoom(room.token(uuid.New()), types.CreateRoomRequest{}))
+
+	a, aID := room.join("a")
+	b, bID := room.join("b")
+	c, _ := room.join("c")
+
+	control := func(ds *testSender, msgType protocol.MessageType, name string) []types.Message {
+		messages, err := room.service.relay(ds, protocol.NewPacket(msgType, 1, []byte(name)))
+		assert.NoError(t, err)
+		return messages
+	}
+
+	messages := control(a, protocol.TypeGroupJoin, "red")
+	assert.Equal(t, []types.Group{{Name: "red", Users: []uuid.UUID{aID}}}, groupEvents(t, messages, protocol.TypeGroupJoined))
+	assert.Len(t, messages[0].Users, 3)
+	assert.Empty(t, control(a, protocol.TypeGroupJoin, "red"), "joining twice changes nothing")
+	control(b, protocol.TypeGroupJoin, "red")
+
+	packet := protocol.NewPacket(protocol.TypeData, 1, []byte("team"))
+	packet.Header.Flags |= protocol.FlagTarget
+	packet.Target = protocol.Target{Kind: protocol.TargetGroup, Group: "red"}
+	messages, err := room.service.relay(c, packet)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []uuid.UUID{aID, bID}, recipients(messages))
+
+	assert.NoError(t, room.service.JoinGroup(room.token(bID), "blue"))
+	groups, err := room.service.GetGroups(room.token(aID))
+	assert.NoError(t, err)
+	assert.Equal(t, []types.Group{
+		{Name: "blue", Users: []uuid.UUID{bID}},
+		{Name: "red", Users: []uuid.UUID{aID, bID}},
+	}, groups)
+	assert.Len(t, groupEvents(t, room.service.Tick(time.Now()), protocol.TypeGroupJoined), 1, "API changes are sent on the next tick")
+
+	_, dID := room.join("d")
+	_, messages, err = room.service.setNewUser(&testSender{id: "e"}, []byte(room.token(uuid.New())), nil, false)
+	assert.NoError(t, err)
+	assert.Equal(t, groups, groupEvents(t, messages, protocol.TypeGroupJoined), "late joiners receive every group")
+	assert.Equal(t, errors.ErrUserNotFound, room.service.JoinGroup(room.token(uuid.New()), "red"))
+
+	sess, _ := room.service.getSessionByAddress(a)
+	room.service.removeSession(nil, sess)
+	control(b, protocol.TypeGroupLeave, "blue")
+	messages = control(b, protocol.TypeGroupLeave, "red")
+	assert.Equal(t, []types.Group{{Name: "red", Users: []uuid.UUID{bID}}}, groupEvents(t, messages, protocol.TypeGroupLeft))
+
+	groups, err = room.service.GetGroups(room.token(dID))
+	assert.NoError(t, err)
+	assert.Empty(t, groups, "groups are removed with their last member")
+
+	_, err = room.service.relay(c, packet)
+	assert.Equal(t, errors.ErrGroupNotFound, err)
+}
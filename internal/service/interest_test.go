@@ -0,0 +1,43 @@
+package service
+
+import (
+	"github.com/ascenmmo/udp-server/pkg/api/types"
+	"github.com/ascenmmo/udp-server/pkg/errors"
+	"github.com/ascenmmo/udp-server/pkg/protocol"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestInterestFiltersRoomMessages(t *testing.T) {
+	room := newTestRoom(t, 1200)
+	assert.Equal(t, errors.ErrBadInterest, room.service.CreateRoom(room.token(uuid.New()), types.CreateRoomRequest{Interest: types.Interest{Radius: 10}}))
+	assert.NoError(t, room.service.CreateRoom(room.token(uuid.New()), types.CreateRoomRequest{Interest: types.Interest{CellSize: 10, Radius: 10}}))
+
+	a, _ := room.join("a")
+	b, bID := room.join("b")
+	c, _ := room.join("c")
+	_, dID := room.join("d")
+
+	send := func(ds *testSender, flags protocol.Flags, position protocol.Position, payload string) []uuid.UUID {
+		packet := protocol.NewPacket(protocol.TypeData, 1, []byte(payload))
+		packet.Header.Flags |= flags
+		packet.Position = position
+		messages, err := room.service.relay(ds, packet)
+		assert.NoError(t, err)
+		return recipients(messages)
+	}
+
+	assert.Empty(t, send(b, protocol.FlagPosition, protocol.Position{X: 5}, ""), "a position update is not relayed")
+	assert.Empty(t, send(c, protocol.FlagPosition, protocol.Position{X: 50}, ""))
+
+	assert.ElementsMatch(t, []uuid.UUID{bID, dID}, send(a, protocol.FlagPosition, protocol.Position{}, "near"), "d has no position and is not filtered")
+	assert.Len(t, send(a, protocol.FlagBypass, protocol.Position{}, "everyone"), 3)
+
+	packet := protocol.NewPacket(protocol.TypeData, 1, []byte("whisper"))
+	packet.Header.Flags |= protocol.FlagTarget
+	packet.Target = protocol.Target{Kind: protocol.TargetUsers, Users: []uuid.UUID{bID}}
+	messages, err := room.service.relay(c, packet)
+	assert.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{bID}, recipients(messages), "explicit targets are not filtered")
+}
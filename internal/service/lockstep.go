@@ -0,0 +1,113 @@
+package service
+
+import (
+	"github.com/ascenmmo/udp-server/internal/connection"
+	"github.com/ascenmmo/udp-server/pkg/api/types"
+	"github.com/ascenmmo/udp-server/pkg/errors"
+	"github.com/ascenmmo/udp-server/pkg/protocol"
+	"github.com/google/uuid"
+	"time"
+)
+
+const (
+	maxResentFrames = 64
+)
+
+// input records the input of a lockstep room member and sends the frames it
+// completes.
+func (s *service) input(ds connection.DataSender, packet protocol.Packet) (messages []types.Message, err error) {
+	sess, packet, err := s.getSession(ds, packet)
+	if err != nil {
+		return s.reack(sess, packet, err)
+	}
+	if packet.IsFragment() {
+		return nil, errors.ErrPacketBadInput
+	}
+
+	room, err := s.getRoomByClientInfo(sess.Info)
+	if err != nil {
+		return nil, err
+	}
+	if room.Lockstep == nil {
+		return nil, errors.ErrRoomNotLockstep
+	}
+
+	inputs := []protocol.Packet{packet}
+	if packet.IsReliable() {
+		messages = s.reply(messages, ds, protocol.NewPacket(protocol.TypeAck, packet.Header.Sequence, protocol.AckPayload(packet.Reliable)))
+		inputs = sess.Reliable.Receive(packet)
+	}
+
+	now := time.Now()
+	for _, in := range inputs {
+		frame, input, err := protocol.ParseInput(in.Payload)
+		if err != nil {
+			return messages, err
+		}
+		err = room.Lockstep.Add(sess.Info.UserID, frame, input, now)
+		if err != nil {
+			return messages, err
+		}
+	}
+
+	return s.closeFrames(messages, room, now), nil
+}
+
+// resendFrames sends a member the frames it asks for again, at most
+// maxResentFrames per request.
+func (s *service) resendFrames(ds connection.DataSender, packet protocol.Packet) (messages []types.Message, err error) {
+	sess, packet, err := s.getSession(ds, packet)
+	if err != nil {
+		return nil, err
+	}
+
+	room, err := s.getRoomByClientInfo(sess.Info)
+	if err != nil {
+		return nil, err
+	}
+	if room.Lockstep == nil {
+		return nil, errors.ErrRoomNotLockstep
+	}
+
+	from, to, err := protocol.ParseFrameRequest(packet.Payload)
+	if err != nil {
+		return nil, err
+	}
+	if to-from >= maxResentFrames {
+		to = from + maxResentFrames - 1
+	}
+
+	user := types.User{ID: sess.Info.UserID, Connection: ds, Session: sess}
+	for _, frame := range room.Lockstep.Frames(from, to) {
+		messages = append(messages, types.Message{
+			Users:  []types.User{user},
+			Packet: protocol.NewPacket(protocol.TypeFrame, frame.Number, frame.Payload),
+		})
+	}
+
+	return messages, nil
+}
+
+// closeFrames sends the frames of a lockstep room that are complete or past
+// their deadline at now. Legacy clients cannot send inputs, so they neither
+// hold frames back nor receive them.
+func (s *service) closeFrames(messages []types.Message, room *types.Room, now time.Time) []types.Message {
+	var members []uuid.UUID
+	var users []types.User
+	for _, user := range room.GetUser() {
+		if user.Legacy {
+			continue
+		}
+		members = append(members, user.ID)
+		users = append(users, *user)
+	}
+
+	for _, frame := range room.Lockstep.Close(members, now) {
+		messages = append(messages, types.Message{
+			Users:  users,
+			Packet: protocol.NewPacket(protocol.TypeFrame, frame.Number, frame.Payload),
+		})
+	}
+
+	return messages
+}
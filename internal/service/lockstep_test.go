@@ -0,0 +1,69 @@
+package service
+
+import (
+	"github.com/ascenmmo/udp-server/pkg/api/types"
+	"github.com/ascenmmo/udp-server/pkg/errors"
+	"github.com/ascenmmo/udp-server/pkg/protocol"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func (r *testRoom) input(ds *testSender, frame uint32, input string) []types.Message {
+	messages, err := r.service.GetUsersAndMessages(ds, protocol.NewPacket(protocol.TypeInput, frame, protocol.InputPayload(frame, []byte(input))))
+	assert.NoError(r.t, err)
+	return messages
+}
+
+func TestLockstepRoom(t *testing.T) {
+	room := newTestRoom(t, 1200)
+	assert.NoError(t, room.service.CreateRoom(room.token(uuid.New()), types.CreateRoomRequest{
+		Mode:         types.RoomModeLockstep,
+		InputDelay:   3,
+		InputTimeout: time.Millisecond * 50,
+	}))
+
+	a, aID := room.join("a")
+	b, bID := room.join("b")
+
+	assert.Empty(t, room.input(a, 1, "a1"))
+	messages := room.input(b, 1, "b1")
+	assert.Len(t, messages, 1)
+	assert.Len(t, messages[0].Users, 2)
+
+	frame, inputs, err := protocol.ParseFrame(messages[0].Packet.Payload)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(4), frame)
+	assert.Len(t, inputs, 2)
+
+	// b misses frame 2; it is sent with b's input missing at the deadline.
+	assert.Empty(t, room.input(a, 2, "a2"))
+	assert.Empty(t, room.service.Tick(time.Now()))
+	messages = room.service.Tick(time.Now().Add(time.Millisecond * 50))
+	assert.Len(t, messages, 1)
+	_, inputs, err = protocol.ParseFrame(messages[0].Packet.Payload)
+	assert.NoError(t, err)
+	for _, in := range inputs {
+		assert.Equal(t, in.UserID == bID, in.Missing)
+		if in.UserID == aID {
+			assert.Equal(t, []byte("a2"), in.Input)
+		}
+	}
+
+	messages, err = room.service.GetUsersAndMessages(b, protocol.NewPacket(protocol.TypeFrameRequest, 1, protocol.FrameRequestPayload(0, 100)))
+	assert.NoError(t, err)
+	assert.Len(t, messages, 2)
+	assert.Equal(t, uint32(4), messages[0].Packet.Header.Sequence)
+	assert.Equal(t, uint32(5), messages[1].Packet.Header.Sequence)
+	assert.Equal(t, bID, messages[0].Users[0].ID)
+
+	_, err = room.service.GetUsersAndMessages(a, protocol.NewPacket(protocol.TypeInput, 2, protocol.InputPayload(2, nil)))
+	assert.ErrorIs(t, err, errors.ErrFrameLate)
+}
+
+func TestCreateRoomRejectsBadMode(t *testing.T) {
+	room := newTestRoom(t, 1200)
+	assert.ErrorIs(t, room.service.CreateRoom(room.token(uuid.New()), types.CreateRoomRequest{Mode: "unknown"}), errors.ErrBadRoomMode)
+	assert.ErrorIs(t, room.service.CreateRoom(room.token(uuid.New()), types.CreateRoomRequest{Mode: types.RoomModeLockstep, TickRate: 30}), errors.ErrBadRoomMode)
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"github.com/ascenmmo/udp-server/pkg/api/types"
+	"github.com/ascenmmo/udp-server/pkg/protocol"
+	"github.com/google/uuid"
+)
+
+// setMetadata applies the metadata entries of a control packet sent by a
+// member and tells every member about the changes.
+func (s *service) setMetadata(messages []types.Message, room *types.Room, userID uuid.UUID, args []byte) ([]types.Message, error) {
+	changes, err := protocol.ParseMetadataEntries(args)
+	if err != nil {
+		return messages, err
+	}
+
+	version, applied, err := room.UpdateMetadata(userID, changes)
+	if err != nil || len(applied) == 0 {
+		return messages, err
+	}
+
+	return s.appendMetadata(messages, room, version, 0, applied), nil
+}
+
+// appendMetadata sends every member of the room, the writer included, a
+// metadata packet. The packet is reliable; legacy clients do not receive it.
+func (s *service) appendMetadata(messages []types.Message, room *types.Room, version uint64, flags byte, entries map[string]string) []types.Message {
+	var users []types.User
+	for _, user := range room.GetUser() {
+		if !user.Legacy {
+			users = append(users, *user)
+		}
+	}
+	if len(users) == 0 {
+		return messages
+	}
+
+	packet := protocol.NewPacket(protocol.TypeMetadata, 0, protocol.MetadataPayload(version, flags, entries))
+	packet.Header.Flags |= protocol.FlagReliable
+
+	return append(messages, types.Message{Users: users, Packet: packet})
+}
+
+// appendMetadataSnapshot sends a user that just joined the room every entry
+// of its metadata.
+func (s *service) appendMetadataSnapshot(messages []types.Message, room *types.Room, user types.User) []types.Message {
+	version, values := room.Metadata()
+	if version == 0 || user.Legacy {
+		return messages
+	}
+
+	packet := protocol.NewPacket(protocol.TypeMetadata, 0, protocol.MetadataPayload(version, protocol.MetadataSnapshot, values))
+	packet.Header.Flags |= protocol.FlagReliable
+
+	return append(messages, types.Message{Users: []types.User{user}, Packet: packet})
+}
+
+// SetRoomMetadata changes metadata keys of the token's room on behalf of the
+// token's user, with the same permissions as a control packet of that user,
+// and returns the new version. A backend token writes every key.
+func (s *service) SetRoomMetadata(token string, request types.SetRoomMetadataRequest) (version uint64, err error) {
+	info, err := s.requestRoom(token, request.GameID, request.RoomID, true)
+	if err != nil {
+		return 0, err
+	}
+
+	room, err := s.getRoomByClientInfo(info)
+	if err != nil {
+		return 0, err
+	}
+
+	version, applied, err := room.UpdateMetadata(info.UserID, request.Values)
+	if err != nil {
+		return 0, err
+	}
+	if len(applied) > 0 {
+		s.send(s.appendMetadata(nil, room, version, 0, applied))
+	}
+
+	return version, nil
+}
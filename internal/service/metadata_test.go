@@ -0,0 +1,118 @@
+package service
+
+import (
+	"github.com/ascenmmo/udp-server/pkg/api/types"
+	"github.com/ascenmmo/udp-server/pkg/errors"
+	"github.com/ascenmmo/udp-server/pkg/protocol"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"time"
+)
+
+type metadataUpdate struct {
+	users   []uuid.UUID
+	version uint64
+	flags   byte
+	entries map[string]string
+}
+
+func metadataUpdates(t *testing.T, messages []types.Message) (updates []metadataUpdate) {
+	for _, msg := range messages {
+		if msg.Packet.Header.Type != protocol.TypeMetadata {
+			continue
+		}
+		assert.True(t, msg.Packet.IsReliable())
+		version, flags, entries, err := protocol.ParseMetadata(msg.Packet.Payload)
+		assert.NoError(t, err)
+		update := metadataUpdate{version: version, flags: flags, entries: entries}
+		for _, user := range msg.Users {
+			update.users = append(update.users, user.ID)
+		}
+		updates = append(updates, update)
+	}
+	return updates
+}
+
+func TestRoomMetadata(t *testing.T) {
+	room := newTestRoom(t, 1200)
+	admin := room.token(uuid.Nil)
+	assert.NoError(t, room.service.CreateRoom(admin, types.CreateRoomRequest{}))
+
+	a, aID := room.join("a")
+	b, bID := room.join("b")
+	set := func(ds *testSender, entries map[string]string) ([]types.Message, error) {
+		args := protocol.AppendMetadataEntries(nil, entries)
+		return room.service.relay(ds, protocol.NewPacket(protocol.TypeControl, 1, protocol.ControlPayload(protocol.CommandSetMetadata, args)))
+	}
+
+	messages, err := set(a, map[string]string{"map": "dust", types.UserMetadataKey(aID, "team"): "red"})
+	assert.NoError(t, err)
+	updates := metadataUpdates(t, messages)
+	assert.Len(t, updates, 1)
+	assert.ElementsMatch(t, []uuid.UUID{aID, bID}, updates[0].users, "the writer receives the change too")
+	assert.Equal(t, uint64(1), updates[0].version)
+	assert.Equal(t, byte(0), updates[0].flags)
+	assert.Len(t, updates[0].entries, 2)
+
+	_, err = set(b, map[string]string{"map": "mirage"})
+	assert.Equal(t, errors.ErrMetadataForbidden, err, "only the owner writes room keys")
+	_, err = set(b, map[string]string{types.UserMetadataKey(bID, "ready"): "1", types.UserMetadataKey(aID, "team"): "blue"})
+	assert.Equal(t, errors.ErrMetadataForbidden, err, "users write only their own keys")
+	messages, err = set(b, map[string]string{types.UserMetadataKey(bID, "ready"): "1"})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(2), metadataUpdates(t, messages)[0].version)
+	messages, err = set(b, map[string]string{types.UserMetadataKey(bID, "ready"): "1"})
+	assert.NoError(t, err)
+	assert.Empty(t, messages, "unchanged values are not sent")
+	_, err = set(a, map[string]string{"big": strings.Repeat("x", types.MaxMetadataValue+1)})
+	assert.Equal(t, errors.ErrMetadataTooLarge, err)
+
+	cID := uuid.New()
+	_, messages, err = room.service.setNewUser(&testSender{id: "c"}, []byte(room.token(cID)), nil, false)
+	assert.NoError(t, err)
+	updates = metadataUpdates(t, messages)
+	assert.Len(t, updates, 1)
+	assert.Equal(t, protocol.MetadataSnapshot, updates[0].flags)
+	assert.Equal(t, uint64(2), updates[0].version)
+	assert.Len(t, updates[0].entries, 3, "a joiner receives every key")
+
+	sess, _ := room.service.getSessionByAddress(b)
+	updates = metadataUpdates(t, room.service.removeSession(nil, sess))
+	assert.Len(t, updates, 1)
+	assert.Equal(t, map[string]string{types.UserMetadataKey(bID, "ready"): ""}, updates[0].entries, "the keys of a leaving user are removed")
+
+	_, err = room.service.SetRoomMetadata(room.token(bID), types.SetRoomMetadataRequest{Values: map[string]string{"map": "mirage"}})
+	assert.Equal(t, errors.ErrMetadataForbidden, err, "tokens write with the permissions of their user")
+	_, err = room.service.SetRoomMetadata(room.token(aID), types.SetRoomMetadataRequest{GameID: uuid.New(), Values: map[string]string{"map": "mirage"}})
+	assert.Equal(t, errors.ErrTokenForbidden, err)
+	version, err := room.service.SetRoomMetadata(room.token(aID), types.SetRoomMetadataRequest{Values: map[string]string{types.UserMetadataKey(aID, "team"): "blue"}})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(4), version)
+	updates = metadataUpdates(t, room.service.Tick(time.Now()))
+	assert.Len(t, updates, 1)
+	assert.Len(t, updates[0].users, 2)
+
+	version, err = room.service.SetRoomMetadata(admin, types.SetRoomMetadataRequest{Values: map[string]string{"map": "mirage", types.UserMetadataKey(cID, "ready"): "1"}})
+	assert.NoError(t, err, "the backend writes every key")
+	assert.Equal(t, uint64(5), version)
+
+	assert.NoError(t, room.service.UpdateRoom(room.token(aID), types.UpdateRoomRequest{Metadata: map[string]string{"mode": "ffa"}}))
+	updates = metadataUpdates(t, room.service.Tick(time.Now()))
+	assert.Len(t, updates, 2)
+	assert.Equal(t, byte(0), updates[1].flags)
+	assert.Equal(t, map[string]string{"map": "", "mode": "ffa", types.UserMetadataKey(aID, "team"): ""}, updates[1].entries, "the owner replaces the keys it may write")
+	assert.Equal(t, errors.ErrMetadataForbidden, room.service.UpdateRoom(room.token(aID), types.UpdateRoomRequest{Metadata: map[string]string{types.UserMetadataKey(cID, "ready"): "0"}}))
+
+	info, err := room.service.GetRoom(admin, types.RoomKey{})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(6), info.MetadataVersion)
+	assert.Equal(t, map[string]string{"mode": "ffa", types.UserMetadataKey(cID, "ready"): "1"}, info.Metadata)
+
+	assert.NoError(t, room.service.UpdateRoom(admin, types.UpdateRoomRequest{Metadata: map[string]string{}}))
+	info, err = room.service.GetRoom(admin, types.RoomKey{})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(7), info.MetadataVersion)
+	assert.Empty(t, info.Metadata, "the backend replaces every key")
+}
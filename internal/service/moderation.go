@@ -0,0 +1,164 @@
+package service
+
+import (
+	tokentype "github.com/ascenmmo/token-generator/token_type"
+	"github.com/ascenmmo/udp-server/internal/utils"
+	"github.com/ascenmmo/udp-server/pkg/api/types"
+	"github.com/ascenmmo/udp-server/pkg/errors"
+	"github.com/ascenmmo/udp-server/pkg/protocol"
+	"github.com/google/uuid"
+	"slices"
+	"strings"
+	"time"
+)
+
+// KickUser removes a member from a room and sends it a disconnect packet. A
+// kick with a duration bans the user for that long. Only the backend and the
+// owner of the room may kick.
+func (s *service) KickUser(token string, request types.KickUserRequest) (err error) {
+	info, err := s.requestRoom(token, request.GameID, request.RoomID, true)
+	if err != nil {
+		return err
+	}
+	if err = s.checkModeration(request.Duration, request.Reason); err != nil {
+		return err
+	}
+
+	room, err := s.getRoomByClientInfo(info)
+	if err != nil {
+		return err
+	}
+	if err = canManage(info, room); err != nil {
+		return err
+	}
+	if _, ok := room.GetUserByID(request.UserID); !ok {
+		return errors.ErrUserNotFound
+	}
+
+	if request.Duration > 0 {
+		s.ban(info, request.UserID, request.Duration, request.Reason)
+	}
+	s.send(s.appendDisconnect(nil, room, request.UserID, protocol.DisconnectKicked, request.Reason))
+
+	return nil
+}
+
+// BanUser refuses the handshakes of a user to a room for the duration, or
+// for good when it is zero, and removes the user when it is a member. Only
+// the backend and the owner of the room may ban; the backend may ban before
+// the room exists.
+func (s *service) BanUser(token string, request types.BanUserRequest) (err error) {
+	info, err := s.requestRoom(token, request.GameID, request.RoomID, true)
+	if err != nil {
+		return err
+	}
+	if err = s.checkModeration(request.Duration, request.Reason); err != nil {
+		return err
+	}
+
+	room, _ := s.getRoomByClientInfo(info)
+	if err = canManage(info, room); err != nil {
+		return err
+	}
+
+	s.ban(info, request.UserID, request.Duration, request.Reason)
+	if room == nil {
+		return nil
+	}
+	s.send(s.appendDisconnect(nil, room, request.UserID, protocol.DisconnectBanned, request.Reason))
+
+	return nil
+}
+
+func (s *service) checkModeration(duration time.Duration, reason string) error {
+	if duration < 0 {
+		return errors.ErrBadBanDuration
+	}
+	if len(reason) > protocol.MaxDisconnectText {
+		return errors.ErrBadReason
+	}
+	return nil
+}
+
+func (s *service) ban(info tokentype.Info, userID uuid.UUID, duration time.Duration, reason string) {
+	info.UserID = userID
+	ban := types.Ban{
+		GameID:    info.GameID,
+		RoomID:    info.RoomID,
+		UserID:    userID,
+		Reason:    reason,
+		CreatedAt: time.Now(),
+	}
+	if duration > 0 {
+		ban.ExpiresAt = ban.CreatedAt.Add(duration)
+	}
+	s.bans.Store(utils.GenerateBanKey(info), ban)
+}
+
+// banned reports whether the token's user is banned from its room.
+func (s *service) banned(info tokentype.Info) bool {
+	key := utils.GenerateBanKey(info)
+	value, ok := s.bans.Load(key)
+	if !ok {
+		return false
+	}
+	if value.(types.Ban).Expired(time.Now()) {
+		s.bans.CompareAndDelete(key, value)
+		return false
+	}
+	return true
+}
+
+// appendDisconnect removes a member from the room and adds a disconnect
+// packet for it along with the notifications of the other members.
+func (s *service) appendDisconnect(messages []types.Message, room *types.Room, userID uuid.UUID, reason protocol.DisconnectReason, text string) []types.Message {
+	user, ok := room.GetUserByID(userID)
+	if !ok {
+		return messages
+	}
+
+	if !user.Legacy {
+		notice := protocol.NewPacket(protocol.TypeDisconnect, 0, protocol.DisconnectPayload(reason, text))
+		messages = append(messages, types.Message{Users: []types.User{*user}, Packet: notice})
+	}
+	return s.removeUser(messages, room, user)
+}
+
+func (s *service) GetRoomBans(token string, room types.RoomKey) (bans []types.Ban, err error) {
+	info, err := s.requestRoom(token, room.GameID, room.RoomID, false)
+	if err != nil {
+		return nil, err
+	}
+	return s.listBans(utils.GenerateRoomBansPrefix(info)), nil
+}
+
+// GetGameBans returns the active bans of every room of the token's game.
+func (s *service) GetGameBans(token string) (bans []types.Ban, err error) {
+	info, err := s.token.ParseToken(token)
+	if err != nil {
+		return nil, err
+	}
+	return s.listBans(utils.GenerateGameBansPrefix(info.GameID)), nil
+}
+
+// listBans returns the active bans whose keys start with prefix, oldest
+// first. Expired bans are dropped on the way.
+func (s *service) listBans(prefix string) (bans []types.Ban) {
+	now := time.Now()
+	bans = []types.Ban{}
+	s.bans.Range(func(key, value any) bool {
+		if !strings.HasPrefix(key.(string), prefix) {
+			return true
+		}
+		ban := value.(types.Ban)
+		if ban.Expired(now) {
+			s.bans.CompareAndDelete(key, value)
+			return true
+		}
+		bans = append(bans, ban)
+		return true
+	})
+	slices.SortFunc(bans, func(a, b types.Ban) int { return a.CreatedAt.Compare(b.CreatedAt) })
+
+	return bans
+}
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"github.com/ascenmmo/udp-server/pkg/api/types"
+	"github.com/ascenmmo/udp-server/pkg/errors"
+	"github.com/ascenmmo/udp-server/pkg/protocol"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestKickAndBan(t *testing.T) {
+	room := newTestRoom(t, 1200)
+	admin := room.token(uuid.Nil)
+	assert.NoError(t, room.service.CreateRoom(admin, types.CreateRoomRequest{}))
+
+	a, aID := room.join("a")
+	_, bID := room.join("b")
+	_, cID := room.join("c")
+
+	rejoin := func(userID uuid.UUID) error {
+		_, _, err := room.service.setNewUser(&testSender{id: uuid.NewString()}, []byte(room.token(userID)), nil, false)
+		return err
+	}
+	assert.Equal(t, errors.ErrTokenForbidden, rejoin(uuid.Nil), "backend tokens do not join")
+
+	assert.Equal(t, errors.ErrNotOwner, room.service.KickUser(room.token(bID), types.KickUserRequest{UserID: cID}), "players other than the owner do not kick")
+	assert.Equal(t, errors.ErrNotOwner, room.service.BanUser(room.token(bID), types.BanUserRequest{UserID: cID}))
+
+	assert.NoError(t, room.service.KickUser(admin, types.KickUserRequest{UserID: aID, Reason: "afk"}))
+	_, ok := room.service.getSessionByAddress(a)
+	assert.False(t, ok)
+	messages := room.service.Tick(time.Now())
+	assert.Len(t, messages, 3, "a disconnect for the user, then a user left and a host changed for the others")
+	assert.Equal(t, protocol.TypeDisconnect, messages[0].Packet.Header.Type)
+	assert.Equal(t, aID, messages[0].Users[0].ID)
+	reason, text, err := protocol.ParseDisconnect(messages[0].Packet.Payload)
+	assert.NoError(t, err)
+	assert.Equal(t, protocol.DisconnectKicked, reason)
+	assert.Equal(t, "afk", text)
+	assert.Equal(t, protocol.TypeUserLeft, messages[1].Packet.Header.Type)
+	assert.Equal(t, protocol.TypeHostChanged, messages[2].Packet.Header.Type)
+	assert.NoError(t, rejoin(aID), "a kick without a duration does not ban")
+
+	assert.NoError(t, room.service.KickUser(admin, types.KickUserRequest{UserID: bID, Duration: time.Hour}))
+	assert.Equal(t, errors.ErrBanned, rejoin(bID))
+	assert.NoError(t, room.service.BanUser(admin, types.BanUserRequest{UserID: cID, Reason: "cheating"}))
+	assert.Equal(t, errors.ErrBanned, rejoin(cID))
+	assert.Equal(t, errors.ErrUserNotFound, room.service.KickUser(admin, types.KickUserRequest{UserID: cID}))
+
+	first := room.info.RoomID
+	room.info.RoomID = uuid.New()
+	second := types.RoomKey{RoomID: room.info.RoomID}
+	secondAdmin := room.token(uuid.Nil)
+	assert.NoError(t, rejoin(cID), "bans are per room")
+	assert.Equal(t, errors.ErrTokenForbidden, room.service.BanUser(admin, types.BanUserRequest{RoomID: second.RoomID, UserID: aID}), "tokens moderate only their own room")
+	assert.NoError(t, room.service.BanUser(secondAdmin, types.BanUserRequest{UserID: aID, Duration: 50 * time.Millisecond}))
+	assert.Equal(t, errors.ErrBanned, rejoin(aID))
+	time.Sleep(60 * time.Millisecond)
+	assert.NoError(t, rejoin(aID), "bans expire")
+
+	bans, err := room.service.GetRoomBans(admin, types.RoomKey{})
+	assert.NoError(t, err)
+	assert.Len(t, bans, 2)
+	assert.Equal(t, bID, bans[0].UserID)
+	assert.Equal(t, first, bans[0].RoomID)
+	assert.Equal(t, "cheating", bans[1].Reason)
+	assert.True(t, bans[1].ExpiresAt.IsZero())
+
+	assert.NoError(t, room.service.BanUser(secondAdmin, types.BanUserRequest{UserID: bID}))
+	bans, err = room.service.GetRoomBans(admin, second)
+	assert.NoError(t, err)
+	assert.Len(t, bans, 1)
+	bans, err = room.service.GetGameBans(admin)
+	assert.NoError(t, err)
+	assert.Len(t, bans, 3)
+
+	foreign := types.RoomKey{GameID: uuid.New(), RoomID: second.RoomID}
+	_, err = room.service.GetRoomBans(admin, foreign)
+	assert.Equal(t, errors.ErrTokenForbidden, err, "tokens see only the bans of their own game")
+	assert.Equal(t, errors.ErrTokenForbidden, room.service.KickUser(admin, types.KickUserRequest{GameID: foreign.GameID, RoomID: foreign.RoomID, UserID: cID}))
+	assert.Equal(t, errors.ErrTokenForbidden, room.service.BanUser(admin, types.BanUserRequest{GameID: foreign.GameID, UserID: cID}))
+
+	assert.Equal(t, errors.ErrBadBanDuration, room.service.BanUser(admin, types.BanUserRequest{UserID: bID, Duration: -time.Second}))
+
+	assert.Equal(t, errors.ErrNotOwner, room.service.KickUser(room.token(aID), types.KickUserRequest{UserID: cID}))
+	assert.NoError(t, room.service.KickUser(room.token(cID), types.KickUserRequest{UserID: aID}), "the owner kicks")
+}
@@ -0,0 +1,104 @@
+package service
+
+import (
+	"github.com/ascenmmo/udp-server/internal/session"
+	"github.com/ascenmmo/udp-server/pkg/api/types"
+	"github.com/ascenmmo/udp-server/pkg/errors"
+	"github.com/ascenmmo/udp-server/pkg/protocol"
+	"github.com/google/uuid"
+)
+
+// control applies a control packet sent by the owner of the room. Start,
+// lock and unlock are relayed to the other members so that they follow the
+// owner; a kicked member receives a disconnect packet. Metadata changes may
+// come from any member.
+func (s *service) control(messages []types.Message, sess *session.Session, room *types.Room, packet protocol.Packet) ([]types.Message, error) {
+	if packet.IsFragment() {
+		full, complete, err := sess.Fragments.Add(packet)
+		if err != nil || !complete {
+			return messages, err
+		}
+		packet.Payload = full
+	}
+
+	command, args, err := protocol.ParseControl(packet.Payload)
+	if err != nil {
+		return messages, err
+	}
+	if command == protocol.CommandSetMetadata {
+		return s.setMetadata(messages, room, sess.Info.UserID, args)
+	}
+	if room.Owner() != sess.Info.UserID {
+		return messages, errors.ErrNotOwner
+	}
+
+	switch command {
+	case protocol.CommandKick:
+		userID, reason, err := protocol.ParseKickArgs(args)
+		if err != nil {
+			return messages, err
+		}
+		if userID == sess.Info.UserID {
+			return messages, errors.ErrPacketBadControl
+		}
+		if _, ok := room.GetUserByID(userID); !ok {
+			return messages, errors.ErrUserNotFound
+		}
+		return s.appendDisconnect(messages, room, userID, protocol.DisconnectKicked, reason), nil
+	case protocol.CommandLock:
+		room.SetLocked(true)
+	case protocol.CommandUnlock:
+		room.SetLocked(false)
+	}
+
+	var users []types.User
+	for _, user := range room.GetUser() {
+		if user.ID != sess.Info.UserID && !user.Legacy {
+			users = append(users, *user)
+		}
+	}
+	if len(users) == 0 {
+		return messages, nil
+	}
+
+	relayed := protocol.NewPacket(protocol.TypeControl, packet.Header.Sequence, packet.Payload)
+	relayed.Header.Flags |= protocol.FlagReliable
+
+	return append(messages, types.Message{Users: users, Packet: relayed}), nil
+}
+
+// appendHostChanged tells every member of the room who its owner is now. The
+// notification is reliable; legacy clients do not receive it.
+func (s *service) appendHostChanged(messages []types.Message, room *types.Room, owner uuid.UUID) []types.Message {
+	var users []types.User
+	for _, user := range room.GetUser() {
+		if !user.Legacy {
+			users = append(users, *user)
+		}
+	}
+	if len(users) == 0 {
+		return messages
+	}
+
+	event := protocol.NewPacket(protocol.TypeHostChanged, 0, owner[:])
+	event.Header.Flags |= protocol.FlagReliable
+
+	return append(messages, types.Message{Users: users, Packet: event})
+}
+
+// appendHost tells a user that just joined the room who its owner is, or
+// every member when the user became the owner.
+func (s *service) appendHost(messages []types.Message, room *types.Room, user types.User) []types.Message {
+	owner := room.Owner()
+	if owner == user.ID {
+		return s.appendHostChanged(messages, room, owner)
+	}
+	if owner == uuid.Nil || user.Legacy {
+		return messages
+	}
+
+	event := protocol.NewPacket(protocol.TypeHostChanged, 0, owner[:])
+	event.Header.Flags |= protocol.FlagReliable
+
+	return append(messages, types.Message{Users: []types.User{user}, Packet: event})
+}
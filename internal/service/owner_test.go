@@ -0,0 +1,93 @@
+package service
+
+import (
+	"github.com/ascenmmo/udp-server/pkg/api/types"
+	"github.com/ascenmmo/udp-server/pkg/errors"
+	"github.com/ascenmmo/udp-server/pkg/protocol"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func hostChanges(t *testing.T, messages []types.Message) (owners []uuid.UUID) {
+	for _, msg := range messages {
+		if msg.Packet.Header.Type != protocol.TypeHostChanged {
+			continue
+		}
+		assert.True(t, msg.Packet.IsReliable())
+		owners = append(owners, uuid.UUID(msg.Packet.Payload))
+	}
+	return owners
+}
+
+func TestRoomOwner(t *testing.T) {
+	room := newTestRoom(t, 1200)
+	assert.NoError(t, room.service.CreateRoom(room.token(uuid.New()), types.CreateRoomRequest{}))
+
+	a, aID := room.join("a")
+	bID := uuid.New()
+	b := &testSender{id: "b"}
+	_, messages, err := room.service.setNewUser(b, []byte(room.token(bID)), nil, false)
+	assert.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{aID}, hostChanges(t, messages), "the first member owns the room")
+
+	packet := protocol.NewPacket(protocol.TypeData, 1, []byte("to host"))
+	packet.Header.Flags |= protocol.FlagTarget
+	packet.Target = protocol.Target{Kind: protocol.TargetOwner}
+	messages, err = room.service.relay(b, packet)
+	assert.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{aID}, recipients(messages))
+
+	control := func(ds *testSender, command protocol.Command, args []byte) ([]types.Message, error) {
+		return room.service.relay(ds, protocol.NewPacket(protocol.TypeControl, 1, protocol.ControlPayload(command, args)))
+	}
+	_, err = control(b, protocol.CommandLock, nil)
+	assert.Equal(t, errors.ErrNotOwner, err)
+
+	messages, err = control(a, protocol.CommandLock, nil)
+	assert.NoError(t, err)
+	assert.Len(t, messages, 1)
+	assert.Equal(t, bID, messages[0].Users[0].ID, "control packets are relayed to the other members")
+	_, _, err = room.service.setNewUser(&testSender{id: "c"}, []byte(room.token(uuid.New())), nil, false)
+	assert.Equal(t, errors.ErrRoomLocked, err)
+	_, err = control(a, protocol.CommandUnlock, nil)
+	assert.NoError(t, err)
+	c, cID := room.join("c")
+	_, dID := room.join("d")
+
+	messages, err = control(a, protocol.CommandKick, protocol.KickArgs(dID, "griefing"))
+	assert.NoError(t, err)
+	assert.Equal(t, protocol.TypeDisconnect, messages[0].Packet.Header.Type)
+	assert.Equal(t, dID, messages[0].Users[0].ID)
+	_, ok := room.service.getSessionByAddress(&testSender{id: "d"})
+	assert.False(t, ok)
+
+	sess, _ := room.service.getSessionByAddress(a)
+	messages = room.service.removeSession(nil, sess)
+	assert.Equal(t, []uuid.UUID{bID}, hostChanges(t, messages), "the member that joined first takes over")
+	assert.Len(t, messages[len(messages)-1].Users, 2)
+	_, err = control(c, protocol.CommandStart, []byte("seed"))
+	assert.Equal(t, errors.ErrNotOwner, err)
+
+	info, err := room.service.GetRoom(room.token(cID), types.RoomKey{})
+	assert.NoError(t, err)
+	assert.Equal(t, bID, info.Owner)
+}
+
+func TestNamedRoomOwner(t *testing.T) {
+	room := newTestRoom(t, 1200)
+	host := uuid.New()
+	assert.NoError(t, room.service.CreateRoom(room.token(uuid.New()), types.CreateRoomRequest{Owner: host}))
+
+	a, _ := room.join("a")
+	packet := protocol.NewPacket(protocol.TypeData, 1, []byte("to host"))
+	packet.Header.Flags |= protocol.FlagTarget
+	packet.Target = protocol.Target{Kind: protocol.TargetOwner}
+	_, err := room.service.relay(a, packet)
+	assert.Equal(t, errors.ErrOwnerNotFound, err, "the owner has not joined yet")
+
+	_, messages, err := room.service.setNewUser(&testSender{id: "host"}, []byte(room.token(host)), nil, false)
+	assert.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{host}, hostChanges(t, messages))
+	assert.Len(t, messages[len(messages)-1].Users, 2, "every member learns that the owner joined")
+}
@@ -0,0 +1,319 @@
+package service
+
+import (
+	"bytes"
+	tokentype "github.com/ascenmmo/token-generator/token_type"
+	"github.com/ascenmmo/udp-server/internal/connection"
+	"github.com/ascenmmo/udp-server/internal/interest"
+	"github.com/ascenmmo/udp-server/internal/session"
+	"github.com/ascenmmo/udp-server/pkg/api/types"
+	"github.com/ascenmmo/udp-server/pkg/errors"
+	"github.com/ascenmmo/udp-server/pkg/protocol"
+	"github.com/google/uuid"
+	"time"
+)
+
+const (
+	tokenTypeSeparator = "$_$"
+)
+
+func (s *service) GetUsersAndMessages(ds connection.DataSender, packet protocol.Packet) (messages []types.Message, err error) {
+	if packet.Legacy {
+		return s.getLegacyUsersAndMessages(ds, packet)
+	}
+
+	switch packet.Header.Type {
+	case protocol.TypeHandshake:
+		return s.handshake(ds, packet)
+	case protocol.TypeData, protocol.TypeGroupJoin, protocol.TypeGroupLeave, protocol.TypeControl:
+		return s.relay(ds, packet)
+	case protocol.TypePing:
+		return s.ping(ds, packet)
+	case protocol.TypePong:
+		return nil, s.pong(ds, packet)
+	case protocol.TypeLeave:
+		return s.leave(ds, packet)
+	case protocol.TypeAck:
+		return nil, s.ack(ds, packet)
+	case protocol.TypeInput:
+		return s.input(ds, packet)
+	case protocol.TypeFrameRequest:
+		return s.resendFrames(ds, packet)
+	case protocol.TypeRollbackInput:
+		return s.rollbackInput(ds, packet)
+	default:
+		return nil, errors.ErrPacketBadType
+	}
+}
+
+// handshake creates the user only once the client has echoed a cookie issued
+// for its address, so spoofed handshakes never allocate state. Encrypted
+// handshakes carry the client's public key in front of the token. A client
+// refused by its room is answered with a reject packet.
+func (s *service) handshake(ds connection.DataSender, packet protocol.Packet) (messages []types.Message, err error) {
+	if !packet.Header.Flags.Has(protocol.FlagCookie) || !s.cookie.Verify(ds.GetID(), packet.Cookie) {
+		return s.retry(messages, ds, packet), nil
+	}
+
+	token := packet.Payload
+	var peerKey []byte
+	if packet.Header.Flags.Has(protocol.FlagEncrypted) {
+		if len(token) < protocol.PublicKeySize {
+			return nil, errors.ErrPacketTooShort
+		}
+		peerKey, token = token[:protocol.PublicKeySize], token[protocol.PublicKeySize:]
+	}
+
+	sess, messages, err := s.setNewUser(ds, token, peerKey, false)
+	if errors.CodeOf(err) != errors.CodeUnknown {
+		s.logger.Debug().Err(err).Str("addr", ds.GetID()).Msg("handshake rejected")
+		return s.reply(nil, ds, protocol.NewPacket(protocol.TypeReject, packet.Header.Sequence, protocol.RejectPayload(err))), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s.reply(messages, ds, protocol.NewPacket(protocol.TypeHandshake, packet.Header.Sequence, sess.HandshakeReply().Marshal())), nil
+}
+
+// retry answers an unverified handshake with a cookie. The answer is never
+// larger than the request, so the server cannot be used for amplification.
+func (s *service) retry(messages []types.Message, ds connection.DataSender, packet protocol.Packet) []types.Message {
+	retry := protocol.NewPacket(protocol.TypeRetry, packet.Header.Sequence, s.cookie.New(ds.GetID()))
+	if retry.Size() > packet.Size() {
+		return messages
+	}
+	return s.reply(messages, ds, retry)
+}
+
+// relay handles the packets a member sends to its room: data and the control
+// messages. Reliable packets are handled in the order they were sent.
+func (s *service) relay(ds connection.DataSender, packet protocol.Packet) (messages []types.Message, err error) {
+	sess, packet, err := s.getSession(ds, packet)
+	if err != nil {
+		return s.reack(sess, packet, err)
+	}
+
+	room, err := s.getRoomByClientInfo(sess.Info)
+	if err != nil {
+		return nil, err
+	}
+
+	if room.Interest != nil && packet.Header.Flags.Has(protocol.FlagPosition) {
+		room.Interest.Move(sess.Info.UserID, interest.Position{X: float64(packet.Position.X), Y: float64(packet.Position.Y)})
+	}
+
+	if !packet.IsReliable() {
+		return s.deliver(messages, ds, sess, room, packet)
+	}
+
+	messages = s.reply(messages, ds, protocol.NewPacket(protocol.TypeAck, packet.Header.Sequence, protocol.AckPayload(packet.Reliable)))
+	for _, delivered := range sess.Reliable.Receive(packet) {
+		messages, err = s.deliver(messages, ds, sess, room, delivered)
+		if err != nil {
+			return messages, err
+		}
+	}
+
+	return messages, nil
+}
+
+func (s *service) deliver(messages []types.Message, ds connection.DataSender, sess *session.Session, room *types.Room, packet protocol.Packet) ([]types.Message, error) {
+	switch packet.Header.Type {
+	case protocol.TypeGroupJoin, protocol.TypeGroupLeave:
+		return s.group(messages, sess, room, packet)
+	case protocol.TypeControl:
+		return s.control(messages, sess, room, packet)
+	default:
+		return s.appendRoomMessage(messages, ds, sess, room, packet)
+	}
+}
+
+// appendRoomMessage adds a client packet to the fan-out to its target.
+// Fragments are held back until their group is complete, so that the room
+// receives full logical messages. Rooms in tick mode keep the message for
+// their next tick. A packet that only carries a position is not relayed.
+func (s *service) appendRoomMessage(messages []types.Message, ds connection.DataSender, sess *session.Session, room *types.Room, packet protocol.Packet) ([]types.Message, error) {
+	payload := packet.Payload
+	if packet.IsFragment() {
+		full, complete, err := sess.Fragments.Add(packet)
+		if err != nil || !complete {
+			return messages, err
+		}
+		payload = full
+	}
+
+	if len(payload) == 0 && packet.Header.Flags.Has(protocol.FlagPosition) {
+		return messages, nil
+	}
+
+	users, err := s.targetUsers(ds, &sess.Info, room, packet.Target)
+	if err != nil {
+		return messages, err
+	}
+	filtered := s.isFiltered(room, packet)
+	if filtered {
+		users = s.filterInterest(room, sess.Info.UserID, users)
+	}
+
+	room.Traffic.In(len(payload))
+	if room.TickRate > 0 {
+		var to []uuid.UUID
+		if packet.Target.Kind != protocol.TargetOthers || filtered {
+			to = make([]uuid.UUID, 0, len(users))
+			for _, user := range users {
+				to = append(to, user.ID)
+			}
+		}
+		room.Hold(sess.Info.UserID, to, payload, packet.IsReliable())
+		return messages, nil
+	}
+
+	msg := protocol.NewPacket(protocol.TypeData, packet.Header.Sequence, payload)
+	if packet.IsReliable() {
+		msg.Header.Flags |= protocol.FlagReliable
+	}
+	room.Traffic.Out(len(users), len(payload))
+
+	return append(messages, types.Message{Users: users, Packet: msg}), nil
+}
+
+func (s *service) ping(ds connection.DataSender, packet protocol.Packet) (messages []types.Message, err error) {
+	if packet.Header.Flags.Has(protocol.FlagSession) {
+		_, packet, err = s.getSession(ds, packet)
+		if err != nil {
+			return nil, err
+		}
+	} else if sess, ok := s.getSessionByAddress(ds); ok {
+		sess.Touch()
+	}
+	return s.reply(messages, ds, protocol.NewPacket(protocol.TypePong, packet.Header.Sequence, packet.Payload)), nil
+}
+
+// pong completes a ping sent by PingUsers.
+func (s *service) pong(ds connection.DataSender, packet protocol.Packet) (err error) {
+	sess, packet, err := s.getSession(ds, packet)
+	if err != nil {
+		return err
+	}
+
+	id, err := protocol.ParsePing(packet.Payload)
+	if err != nil {
+		return err
+	}
+
+	sess.Link.Pong(id, time.Now())
+
+	return nil
+}
+
+func (s *service) ack(ds connection.DataSender, packet protocol.Packet) (err error) {
+	sess, packet, err := s.getSession(ds, packet)
+	if err != nil {
+		return err
+	}
+
+	sequences, err := protocol.ParseAck(packet.Payload)
+	if err != nil {
+		return err
+	}
+
+	sess.Reliable.Ack(sequences...)
+
+	return nil
+}
+
+func (s *service) leave(ds connection.DataSender, packet protocol.Packet) (messages []types.Message, err error) {
+	sess, _, err := s.getSession(ds, packet)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.removeSession(messages, sess), nil
+}
+
+// reack acknowledges again a reliable packet that the replay window of an
+// encrypted session dropped. A client whose ack was lost repeats the packet,
+// and without a new ack it would retransmit it until it gives up. The ack
+// goes to the session's current address.
+func (s *service) reack(sess *session.Session, packet protocol.Packet, err error) ([]types.Message, error) {
+	if sess == nil || err != errors.ErrPacketReplayed || !packet.IsReliable() {
+		return nil, err
+	}
+	return s.reply(nil, sess.Connection(), protocol.NewPacket(protocol.TypeAck, packet.Header.Sequence, protocol.AckPayload(packet.Reliable))), nil
+}
+
+func (s *service) reply(messages []types.Message, ds connection.DataSender, packet protocol.Packet) []types.Message {
+	return append(messages, types.Message{Users: []types.User{{Connection: ds}}, Packet: packet})
+}
+
+// getLegacyUsersAndMessages serves clients that send raw tokens and raw
+// payloads without a protocol header.
+func (s *service) getLegacyUsersAndMessages(ds connection.DataSender, packet protocol.Packet) (messages []types.Message, err error) {
+	var room *types.Room
+	sess, ok := s.getSessionByAddress(ds)
+	if ok {
+		room, err = s.getRoomByClientInfo(sess.Info)
+	}
+	if !ok || err != nil {
+		sess, messages, err := s.setNewUser(ds, packet.Payload, nil, true)
+		if err != nil {
+			return nil, err
+		}
+		return s.legacyReply(messages, ds, []byte(sess.Info.UserID.String())), nil
+	}
+	sess.Touch()
+
+	clientInfo := &sess.Info
+
+	if s.isUserToken(clientInfo, packet.Payload) {
+		return s.legacyReply(messages, ds, []byte(clientInfo.UserID.String())), nil
+	}
+
+	room.Traffic.In(len(packet.Payload))
+	if room.TickRate > 0 {
+		room.Hold(clientInfo.UserID, nil, packet.Payload, false)
+		return messages, nil
+	}
+
+	msg := protocol.NewPacket(protocol.TypeData, 0, packet.Payload)
+	users := s.roomUsersExceptSender(ds, clientInfo, room)
+	room.Traffic.Out(len(users), len(packet.Payload))
+
+	return append(messages, types.Message{Users: users, Packet: msg}), nil
+}
+
+func (s *service) legacyReply(messages []types.Message, ds connection.DataSender, payload []byte) []types.Message {
+	return append(messages, types.Message{
+		Users:  []types.User{{Connection: ds, Legacy: true}},
+		Packet: protocol.NewPacket(protocol.TypeHandshake, 0, payload),
+	})
+}
+
+// isUserToken reports whether a legacy payload is the client's own token sent
+// again to request its user ID. Only payloads carrying the token type suffix
+// are parsed, so ordinary game data is never mistaken for a handshake.
+func (s *service) isUserToken(clientInfo *tokentype.Info, payload []byte) bool {
+	if len(payload) < len(tokenTypeSeparator)+1 ||
+		!bytes.Equal(payload[len(payload)-len(tokenTypeSeparator)-1:len(payload)-1], []byte(tokenTypeSeparator)) {
+		return false
+	}
+
+	info, err := s.token.ParseToken(string(payload))
+	if err != nil {
+		return false
+	}
+
+	return info.UserID == clientInfo.UserID
+}
+
+func (s *service) roomUsersExceptSender(ds connection.DataSender, clientInfo *tokentype.Info, room *types.Room) (users []types.User) {
+	usersData := room.GetUser()
+	for _, v := range usersData {
+		if v.ID == clientInfo.UserID &&
+			ds.GetID() == v.Connection.GetID() {
+			continue
+		}
+		users = append(users, *v)
+	}
+	return users
+}
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"crypto/ecdh"
+	"crypto/rand"
+	"github.com/ascenmmo/udp-server/pkg/api/types"
+	"github.com/ascenmmo/udp-server/pkg/errors"
+	"github.com/ascenmmo/udp-server/pkg/protocol"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestHandshakeCookie(t *testing.T) {
+	room := newTestRoom(t, 1200)
+	token := room.token(uuid.New())
+	ds := &testSender{id: "spoofed"}
+
+	packet := protocol.NewPacket(protocol.TypeHandshake, 1, []byte(token))
+	messages, err := room.service.handshake(ds, packet)
+	assert.NoError(t, err)
+	assert.Len(t, messages, 1)
+	retry := messages[0].Packet
+	assert.Equal(t, protocol.TypeRetry, retry.Header.Type)
+	assert.LessOrEqual(t, retry.Size(), packet.Size(), "the retry is no larger than the handshake")
+
+	_, ok := room.service.getSessionByAddress(ds)
+	assert.False(t, ok, "no session before the cookie comes back")
+	count, _ := room.service.GetConnectionsNum()
+	assert.Zero(t, count)
+	_, err = room.service.GetRoom(token, types.RoomKey{})
+	assert.Equal(t, errors.ErrRoomNotFound, err, "no room before the cookie comes back")
+
+	messages, err = room.service.handshake(ds, protocol.NewPacket(protocol.TypeHandshake, 1, []byte("x")))
+	assert.NoError(t, err)
+	assert.Empty(t, messages, "handshakes smaller than the retry get no answer")
+
+	packet.Header.Flags |= protocol.FlagCookie
+	packet.Cookie = retry.Payload
+	messages, err = room.service.handshake(ds, packet)
+	assert.NoError(t, err)
+	assert.Equal(t, protocol.TypeHandshake, messages[len(messages)-1].Packet.Header.Type)
+	_, ok = room.service.getSessionByAddress(ds)
+	assert.True(t, ok)
+}
+
+func TestReackReplayedReliablePacket(t *testing.T) {
+	room := newTestRoom(t, 1200)
+	_, bID := room.join("b")
+
+	client, err := ecdh.X25519().GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	a := &testSender{id: "a"}
+	sess, _, err := room.service.setNewUser(a, []byte(room.token(uuid.New())), client.PublicKey().Bytes(), false)
+	assert.NoError(t, err)
+
+	server, err := ecdh.X25519().NewPublicKey(sess.HandshakeReply().PublicKey)
+	assert.NoError(t, err)
+	shared, err := client.ECDH(server)
+	assert.NoError(t, err)
+	encryptionKey, _ := protocol.DeriveSessionKeys(shared, sess.ID)
+	cipher, err := protocol.NewCipher(encryptionKey)
+	assert.NoError(t, err)
+
+	packet := protocol.NewPacket(protocol.TypeData, 1, []byte("event"))
+	packet.Header.Flags |= protocol.FlagReliable
+	packet.Reliable = 1
+	sealed := cipher.Seal(packet, protocol.ClientToServer)
+
+	messages, err := room.service.relay(a, sealed)
+	assert.NoError(t, err)
+	assert.Equal(t, protocol.TypeAck, messages[0].Packet.Header.Type)
+	assert.Equal(t, []uuid.UUID{bID}, recipients(messages))
+
+	messages, err = room.service.relay(a, sealed)
+	assert.NoError(t, err, "the ack was lost and the client repeats the packet")
+	assert.Len(t, messages, 1)
+	sequences, err := protocol.ParseAck(messages[0].Packet.Payload)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint32{1}, sequences)
+	assert.Empty(t, recipients(messages), "the repeated packet is not delivered again")
+
+	unreliable := cipher.Seal(protocol.NewPacket(protocol.TypeData, 2, []byte("move")), protocol.ClientToServer)
+	_, err = room.service.relay(a, unreliable)
+	assert.NoError(t, err)
+	_, err = room.service.relay(a, unreliable)
+	assert.Equal(t, errors.ErrPacketReplayed, err, "unreliable replays are still dropped")
+}
+
+func TestLegacyClients(t *testing.T) {
+	room := newTestRoom(t, 1200)
+	legacy := func(ds *testSender, payload string) []types.Message {
+		messages, err := room.service.GetUsersAndMessages(ds, protocol.Packet{Legacy: true, Payload: []byte(payload)})
+		assert.NoError(t, err)
+		return messages
+	}
+
+	a, aID := &testSender{id: "a"}, uuid.New()
+	messages := legacy(a, room.token(aID))
+	reply := messages[len(messages)-1]
+	assert.True(t, reply.Users[0].Legacy)
+	assert.Equal(t, []byte(aID.String()), reply.Packet.Marshal(true), "a raw token is answered with the user ID")
+
+	b, bID := &testSender{id: "b"}, uuid.New()
+	legacy(b, room.token(bID))
+	_, cID := room.join("c")
+
+	messages = legacy(a, "hello")
+	assert.Len(t, messages, 1)
+	assert.ElementsMatch(t, []uuid.UUID{bID, cID}, recipients(messages))
+	for _, user := range messages[0].Users {
+		assert.Equal(t, user.ID == bID, user.Legacy)
+	}
+	assert.Equal(t, []byte("hello"), messages[0].Packet.Marshal(true), "legacy members receive the payload unframed")
+
+	messages = legacy(a, room.token(aID))
+	assert.Len(t, messages, 1)
+	assert.Empty(t, recipients(messages), "the client's own token is not relayed")
+	assert.Equal(t, []byte(aID.String()), messages[0].Packet.Marshal(true))
+
+	for _, payload := range []string{"score$_$1", room.token(bID)} {
+		messages = legacy(a, payload)
+		assert.ElementsMatch(t, []uuid.UUID{bID, cID}, recipients(messages), "%q is game data", payload)
+		assert.Equal(t, []byte(payload), messages[0].Packet.Marshal(true))
+	}
+}
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"github.com/ascenmmo/udp-server/internal/connection"
+	"github.com/ascenmmo/udp-server/pkg/api/types"
+	"github.com/ascenmmo/udp-server/pkg/errors"
+	"github.com/ascenmmo/udp-server/pkg/protocol"
+	"github.com/google/uuid"
+)
+
+// rollbackInput records the inputs and the confirmed frame of a rollback
+// room member. Every other member is sent the inputs it has not confirmed
+// yet, so each packet repeats the recent inputs that may have been lost. A
+// packet without inputs asks for the sender's own missing inputs.
+func (s *service) rollbackInput(ds connection.DataSender, packet protocol.Packet) (messages []types.Message, err error) {
+	sess, packet, err := s.getSession(ds, packet)
+	if err != nil {
+		return nil, err
+	}
+	if packet.IsFragment() {
+		return nil, errors.ErrPacketBadInput
+	}
+
+	room, err := s.getRoomByClientInfo(sess.Info)
+	if err != nil {
+		return nil, err
+	}
+	if room.Rollback == nil {
+		return nil, errors.ErrRoomNotRollback
+	}
+
+	confirmed, first, inputs, err := protocol.ParseRollbackInput(packet.Payload)
+	if err != nil {
+		return nil, err
+	}
+	if err = room.Rollback.Add(sess.Info.UserID, confirmed, first, inputs); err != nil {
+		return nil, err
+	}
+
+	request := len(inputs) == 0
+	var members []uuid.UUID
+	var users []types.User
+	for _, user := range room.GetUser() {
+		if user.Legacy {
+			continue
+		}
+		members = append(members, user.ID)
+
+		sender := user.ID == sess.Info.UserID
+		if sender == request {
+			users = append(users, *user)
+		}
+	}
+
+	for _, user := range users {
+		missing := room.Rollback.Missing(user.ID, members)
+		if len(missing) == 0 {
+			continue
+		}
+		messages = append(messages, types.Message{
+			Users:  []types.User{user},
+			Packet: protocol.NewPacket(protocol.TypeRollbackInputs, packet.Header.Sequence, protocol.RollbackInputsPayload(missing)),
+		})
+	}
+
+	return messages, nil
+}
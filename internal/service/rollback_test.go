@@ -0,0 +1,62 @@
+package service
+
+import (
+	"github.com/ascenmmo/udp-server/pkg/api/types"
+	"github.com/ascenmmo/udp-server/pkg/errors"
+	"github.com/ascenmmo/udp-server/pkg/protocol"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func (r *testRoom) rollbackInput(ds *testSender, confirmed, first uint32, inputs ...string) []types.Message {
+	var in [][]byte
+	for _, input := range inputs {
+		in = append(in, []byte(input))
+	}
+	packet := protocol.NewPacket(protocol.TypeRollbackInput, first, protocol.RollbackInputPayload(confirmed, first, in))
+	messages, err := r.service.GetUsersAndMessages(ds, packet)
+	assert.NoError(r.t, err)
+	return messages
+}
+
+func rollbackInputsFor(t *testing.T, messages []types.Message, userID uuid.UUID) []protocol.PlayerInputs {
+	for _, msg := range messages {
+		if msg.Users[0].ID == userID {
+			players, err := protocol.ParseRollbackInputs(msg.Packet.Payload)
+			assert.NoError(t, err)
+			return players
+		}
+	}
+	return nil
+}
+
+func TestRollbackRoom(t *testing.T) {
+	room := newTestRoom(t, 1200)
+	assert.NoError(t, room.service.CreateRoom(room.token(uuid.New()), types.CreateRoomRequest{
+		Mode:         types.RoomModeRollback,
+		InputHistory: 16,
+	}))
+
+	a, aID := room.join("a")
+	b, bID := room.join("b")
+
+	// a's packets reach b with every input b has not confirmed.
+	messages := room.rollbackInput(a, 0, 1, "a1")
+	assert.Len(t, messages, 1)
+	assert.Equal(t, []protocol.PlayerInputs{{UserID: aID, First: 1, Inputs: [][]byte{[]byte("a1")}}}, rollbackInputsFor(t, messages, bID))
+
+	messages = room.rollbackInput(a, 0, 1, "a1", "a2")
+	assert.Len(t, rollbackInputsFor(t, messages, bID)[0].Inputs, 2)
+
+	// b confirms frame 1 and sends its own inputs to a.
+	messages = room.rollbackInput(b, 1, 1, "b1", "b2")
+	assert.Equal(t, []protocol.PlayerInputs{{UserID: bID, First: 1, Inputs: [][]byte{[]byte("b1"), []byte("b2")}}}, rollbackInputsFor(t, messages, aID))
+
+	// A packet without inputs asks for what the sender misses.
+	messages = room.rollbackInput(b, 1, 0)
+	assert.Len(t, messages, 1)
+	assert.Equal(t, []protocol.PlayerInputs{{UserID: aID, First: 2, Inputs: [][]byte{[]byte("a2")}}}, rollbackInputsFor(t, messages, bID))
+
+	assert.ErrorIs(t, room.service.CreateRoom(room.token(uuid.New()), types.CreateRoomRequest{Mode: types.RoomModeRollback, InputHistory: -1}), errors.ErrBadInputHistory)
+}
@@ -0,0 +1,223 @@
+package service
+
+import (
+	tokentype "github.com/ascenmmo/token-generator/token_type"
+	"github.com/ascenmmo/udp-server/internal/utils"
+	"github.com/ascenmmo/udp-server/pkg/api/types"
+	"github.com/ascenmmo/udp-server/pkg/errors"
+	"github.com/ascenmmo/udp-server/pkg/protocol"
+	"github.com/google/uuid"
+	"slices"
+	"strings"
+	"time"
+)
+
+// DefaultRoomsLimit is the page size of ListRooms when none is requested,
+// and MaxRoomsLimit the largest one.
+const (
+	DefaultRoomsLimit = 100
+	MaxRoomsLimit     = 1000
+)
+
+// requestRoom returns the token info of the room a request is about. A zero
+// game ID stands for the token's game and a zero room ID for its room. A
+// token reaches only the rooms of its own game and, when manage is set, only
+// its own room.
+func (s *service) requestRoom(token string, gameID, roomID uuid.UUID, manage bool) (info tokentype.Info, err error) {
+	info, err = s.token.ParseToken(token)
+	if err != nil {
+		return info, err
+	}
+	if gameID != uuid.Nil && gameID != info.GameID {
+		return info, errors.ErrTokenForbidden
+	}
+	if roomID != uuid.Nil && roomID != info.RoomID {
+		if manage {
+			return info, errors.ErrTokenForbidden
+		}
+		info.RoomID = roomID
+	}
+	return info, nil
+}
+
+// canManage checks that a token may change a room: tokens of the game
+// backend, which carry no user, change any room of their game, and player
+// tokens only the room their user owns. room is nil when it does not exist.
+func canManage(info tokentype.Info, room *types.Room) error {
+	if info.UserID == uuid.Nil || room != nil && room.Owner() == info.UserID {
+		return nil
+	}
+	return errors.ErrNotOwner
+}
+
+// ListRooms returns a page of the rooms of a game, ordered by creation time.
+func (s *service) ListRooms(token string, request types.ListRoomsRequest) (rooms types.RoomList, err error) {
+	info, err := s.requestRoom(token, request.GameID, uuid.Nil, false)
+	if err != nil {
+		return rooms, err
+	}
+	if request.Offset < 0 || request.Limit < 0 || request.Limit > MaxRoomsLimit {
+		return rooms, errors.ErrBadPage
+	}
+	limit := request.Limit
+	if limit == 0 {
+		limit = DefaultRoomsLimit
+	}
+
+	var found []*types.Room
+	s.storage.RangeData(utils.GenerateGameRoomsPrefix(info.GameID), func(_ string, value any) bool {
+		if room, ok := value.(*types.Room); ok {
+			found = append(found, room)
+		}
+		return true
+	})
+	slices.SortFunc(found, func(a, b *types.Room) int {
+		if c := a.CreatedAt.Compare(b.CreatedAt); c != 0 {
+			return c
+		}
+		return strings.Compare(a.RoomID.String(), b.RoomID.String())
+	})
+
+	rooms.Total = len(found)
+	rooms.Rooms = []types.RoomInfo{}
+	for _, room := range found[min(request.Offset, len(found)):min(request.Offset+limit, len(found))] {
+		rooms.Rooms = append(rooms.Rooms, s.roomInfo(room))
+	}
+
+	return rooms, nil
+}
+
+func (s *service) GetRoom(token string, room types.RoomKey) (info types.RoomInfo, err error) {
+	clientInfo, err := s.requestRoom(token, room.GameID, room.RoomID, false)
+	if err != nil {
+		return info, err
+	}
+
+	found, err := s.getRoomByClientInfo(clientInfo)
+	if err != nil {
+		return info, err
+	}
+
+	return s.roomInfo(found), nil
+}
+
+func (s *service) roomInfo(room *types.Room) types.RoomInfo {
+	version, metadata := room.Metadata()
+	info := types.RoomInfo{
+		GameID:          room.GameID,
+		RoomID:          room.RoomID,
+		Users:           []uuid.UUID{},
+		CreatedAt:       room.CreatedAt,
+		UpdatedAt:       room.GetUpdatedAt(),
+		Owner:           room.Owner(),
+		Locked:          room.Locked(),
+		MaxUsers:        room.MaxUsers(),
+		AllowedUsers:    room.AllowedUsers(),
+		Metadata:        metadata,
+		MetadataVersion: version,
+		Traffic:         room.TrafficStats(),
+		Reliable:        room.ReliableStats(),
+	}
+	for _, user := range room.GetUser() {
+		info.Users = append(info.Users, user.ID)
+	}
+
+	key := utils.GenerateRoomKey(tokentype.Info{GameID: room.GameID, RoomID: room.RoomID})
+	if expiresAt, ok := s.storage.ExpiresAt(key); ok {
+		info.TTL = max(0, time.Until(expiresAt))
+	}
+
+	return info
+}
+
+// CloseRoom deletes a room and its members' sessions. The members are sent a
+// disconnect packet with the reason, on a best-effort basis. Only the
+// backend and the owner of the room may close it.
+func (s *service) CloseRoom(token string, request types.CloseRoomRequest) (err error) {
+	info, err := s.requestRoom(token, request.GameID, request.RoomID, true)
+	if err != nil {
+		return err
+	}
+	if len(request.Reason) > protocol.MaxDisconnectText {
+		return errors.ErrBadReason
+	}
+
+	room, err := s.getRoomByClientInfo(info)
+	if err != nil {
+		return err
+	}
+	if err = canManage(info, room); err != nil {
+		return err
+	}
+
+	roomKey := utils.GenerateRoomKey(info)
+	s.storage.Remove(roomKey)
+	s.ticking.Delete(roomKey)
+
+	var users []types.User
+	for _, user := range room.GetUser() {
+		room.RemoveUser(user.ID)
+		if user.Session != nil {
+			s.closeSession(user.Session)
+		}
+		if !user.Legacy {
+			users = append(users, *user)
+		}
+	}
+	if len(users) == 0 {
+		return nil
+	}
+
+	notice := protocol.NewPacket(protocol.TypeDisconnect, 0, protocol.DisconnectPayload(protocol.DisconnectRoomClosed, request.Reason))
+	s.send([]types.Message{{Users: users, Packet: notice}})
+
+	return nil
+}
+
+// UpdateRoom changes the settings of a room that are set in the request. A
+// lower MaxUsers does not remove members, it only refuses new ones. New
+// metadata replaces the keys the token may write, as SetRoomMetadata would,
+// and the changes are sent to the members. Only the backend and the owner of
+// the room may change it.
+func (s *service) UpdateRoom(token string, request types.UpdateRoomRequest) (err error) {
+	info, err := s.requestRoom(token, request.GameID, request.RoomID, true)
+	if err != nil {
+		return err
+	}
+	if request.RoomTTl != nil && *request.RoomTTl < 0 {
+		return errors.ErrBadRoomTTL
+	}
+	if request.MaxUsers != nil && *request.MaxUsers < 0 {
+		return errors.ErrBadMaxUsers
+	}
+	if err = types.CheckMetadata(request.Metadata); err != nil {
+		return err
+	}
+
+	room, err := s.getRoomByClientInfo(info)
+	if err != nil {
+		return err
+	}
+	if err = canManage(info, room); err != nil {
+		return err
+	}
+
+	if request.Metadata != nil {
+		version, applied, err := room.ReplaceMetadata(info.UserID, request.Metadata)
+		if err != nil {
+			return err
+		}
+		if len(applied) > 0 {
+			s.send(s.appendMetadata(nil, room, version, 0, applied))
+		}
+	}
+	if request.RoomTTl != nil {
+		s.setRoom(info, room, *request.RoomTTl)
+	}
+	if request.MaxUsers != nil {
+		room.SetMaxUsers(*request.MaxUsers)
+	}
+	room.SetUpdatedAt()
+
+	return nil
+}
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"github.com/ascenmmo/udp-server/pkg/api/types"
+	"github.com/ascenmmo/udp-server/pkg/errors"
+	"github.com/ascenmmo/udp-server/pkg/protocol"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestRoomManagement(t *testing.T) {
+	room := newTestRoom(t, 1200)
+	admin := room.token(uuid.Nil)
+	assert.NoError(t, room.service.CreateRoom(admin, types.CreateRoomRequest{RoomTTl: time.Hour}))
+
+	a, aID := room.join("a")
+	b, bID := room.join("b")
+	_, err := room.service.relay(a, protocol.NewPacket(protocol.TypeData, 1, []byte("hello")))
+	assert.NoError(t, err)
+
+	info, err := room.service.GetRoom(admin, types.RoomKey{})
+	assert.NoError(t, err)
+	assert.Equal(t, room.info.RoomID, info.RoomID)
+	assert.ElementsMatch(t, []uuid.UUID{aID, bID}, info.Users)
+	assert.Equal(t, types.TrafficStats{MessagesIn: 1, BytesIn: 5, MessagesOut: 1, BytesOut: 5}, info.Traffic)
+	assert.InDelta(t, time.Hour, info.TTL, float64(time.Minute))
+
+	maxUsers := 2
+	assert.Equal(t, errors.ErrNotOwner, room.service.UpdateRoom(room.token(bID), types.UpdateRoomRequest{MaxUsers: &maxUsers}), "players other than the owner change nothing")
+	assert.Equal(t, errors.ErrNotOwner, room.service.CloseRoom(room.token(bID), types.CloseRoomRequest{}))
+	assert.NoError(t, room.service.UpdateRoom(room.token(aID), types.UpdateRoomRequest{RoomTTl: &info.TTL}), "the owner changes the room")
+	assert.NoError(t, room.service.UpdateRoom(admin, types.UpdateRoomRequest{MaxUsers: &maxUsers, Metadata: map[string]string{"map": "dust"}}))
+	_, _, err = room.service.setNewUser(&testSender{id: "c"}, []byte(room.token(uuid.New())), nil, false)
+	assert.Equal(t, errors.ErrRoomFull, err)
+	_, _, err = room.service.setNewUser(&testSender{id: "a2"}, []byte(room.token(aID)), nil, false)
+	assert.NoError(t, err, "members join again when the room is full")
+
+	info, err = room.service.GetRoom(admin, types.RoomKey{})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, info.MaxUsers)
+	assert.Equal(t, map[string]string{"map": "dust"}, info.Metadata)
+
+	first := room.info.RoomID
+	room.info.RoomID = uuid.New()
+	assert.NoError(t, room.service.CreateRoom(room.token(uuid.New()), types.CreateRoomRequest{}))
+
+	rooms, err := room.service.ListRooms(admin, types.ListRoomsRequest{Limit: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, rooms.Total)
+	assert.Equal(t, first, rooms.Rooms[0].RoomID, "rooms are listed in creation order")
+	rooms, err = room.service.ListRooms(admin, types.ListRoomsRequest{Offset: 1, Limit: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, room.info.RoomID, rooms.Rooms[0].RoomID)
+
+	_, err = room.service.ListRooms(admin, types.ListRoomsRequest{GameID: uuid.New()})
+	assert.Equal(t, errors.ErrTokenForbidden, err, "tokens reach only the rooms of their own game")
+	_, err = room.service.GetRoom(admin, types.RoomKey{GameID: uuid.New(), RoomID: first})
+	assert.Equal(t, errors.ErrTokenForbidden, err)
+	assert.Equal(t, errors.ErrTokenForbidden, room.service.UpdateRoom(admin, types.UpdateRoomRequest{GameID: uuid.New(), MaxUsers: &maxUsers}))
+	assert.Equal(t, errors.ErrTokenForbidden, room.service.CloseRoom(room.token(uuid.New()), types.CloseRoomRequest{RoomID: first}), "tokens change only their own room")
+
+	assert.NoError(t, room.service.CloseRoom(admin, types.CloseRoomRequest{RoomID: first, Reason: "maintenance"}))
+	_, err = room.service.GetRoom(admin, types.RoomKey{RoomID: first})
+	assert.Equal(t, errors.ErrRoomNotFound, err)
+	_, ok := room.service.getSessionByAddress(b)
+	assert.False(t, ok, "the sessions of a closed room are dropped")
+
+	messages := room.service.Tick(time.Now())
+	assert.Len(t, messages, 2, "the metadata change, then the disconnect")
+	assert.Equal(t, protocol.TypeMetadata, messages[0].Packet.Header.Type)
+	assert.Equal(t, protocol.TypeDisconnect, messages[1].Packet.Header.Type)
+	assert.Len(t, messages[1].Users, 2)
+	reason, text, err := protocol.ParseDisconnect(messages[1].Packet.Payload)
+	assert.NoError(t, err)
+	assert.Equal(t, protocol.DisconnectRoomClosed, reason)
+	assert.Equal(t, "maintenance", text)
+}
+
+func TestHandshakeRejection(t *testing.T) {
+	room := newTestRoom(t, 1200)
+	invited, other := uuid.New(), uuid.New()
+	assert.NoError(t, room.service.CreateRoom(room.token(uuid.New()), types.CreateRoomRequest{
+		MaxUsers:     1,
+		NoAutoCreate: true,
+		AllowedUsers: []uuid.UUID{invited, other},
+	}))
+
+	handshake := func(addr string, userID uuid.UUID) errors.Code {
+		ds := &testSender{id: addr}
+		packet := protocol.NewPacket(protocol.TypeHandshake, 1, []byte(room.token(userID)))
+		packet.Header.Flags |= protocol.FlagCookie
+		packet.Cookie = room.service.cookie.New(addr)
+		messages, err := room.service.handshake(ds, packet)
+		assert.NoError(t, err)
+		reply := messages[len(messages)-1].Packet
+		if reply.Header.Type != protocol.TypeReject {
+			return errors.CodeUnknown
+		}
+		assert.Len(t, messages, 1)
+		code, _, err := protocol.ParseReject(reply.Payload)
+		assert.NoError(t, err)
+		return code
+	}
+
+	assert.Equal(t, errors.CodeNotInvited, handshake("a", uuid.New()))
+	assert.Equal(t, errors.CodeUnknown, handshake("b", invited))
+	assert.Equal(t, errors.CodeRoomFull, handshake("c", other))
+
+	assert.NoError(t, room.service.CloseRoom(room.token(invited), types.CloseRoomRequest{}))
+	assert.Equal(t, errors.CodeRoomNotFound, handshake("b", invited), "the room is not created again")
+
+	room.info.RoomID = uuid.New()
+	assert.Equal(t, errors.CodeUnknown, handshake("d", uuid.New()), "other rooms are still created on join")
+}
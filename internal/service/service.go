@@ -4,29 +4,87 @@ import (
 	tokengenerator "github.com/ascenmmo/token-generator/token_generator"
 	tokentype "github.com/ascenmmo/token-generator/token_type"
 	"github.com/ascenmmo/udp-server/internal/connection"
+	"github.com/ascenmmo/udp-server/internal/cookie"
+	"github.com/ascenmmo/udp-server/internal/fragment"
+	"github.com/ascenmmo/udp-server/internal/interest"
+	"github.com/ascenmmo/udp-server/internal/link"
+	"github.com/ascenmmo/udp-server/internal/lockstep"
+	"github.com/ascenmmo/udp-server/internal/reliable"
+	"github.com/ascenmmo/udp-server/internal/rollback"
+	"github.com/ascenmmo/udp-server/internal/session"
 	memoryDB "github.com/ascenmmo/udp-server/internal/storage"
 	"github.com/ascenmmo/udp-server/internal/utils"
 	"github.com/ascenmmo/udp-server/pkg/api/types"
 	"github.com/ascenmmo/udp-server/pkg/errors"
+	"github.com/ascenmmo/udp-server/pkg/protocol"
 	"github.com/google/uuid"
 	"github.com/rs/zerolog"
+	"sync"
 	"time"
 )
 
 type Service interface {
 	GetConnectionsNum() (countConn int, exists bool)
 	CreateRoom(token string, room types.CreateRoomRequest) error
-	GetUsersAndMessage(ds connection.DataSender, req []byte) (users []types.User, msg []byte, err error)
-	RemoveUser(ds connection.DataSender, userID uuid.UUID) (err error)
+	GetUsersAndMessages(ds connection.DataSender, packet protocol.Packet) (messages []types.Message, err error)
+	RoutingKey(ds connection.DataSender, packet protocol.Packet) (key string)
+	RemoveUser(ds connection.DataSender, userID uuid.UUID) (messages []types.Message, err error)
+	RemoveIdleUsers() (messages []types.Message)
 	GetDeletedRooms(token string, ids []types.GetDeletedRooms) (deletedIds []types.GetDeletedRooms, err error)
+	GetRoomStats(token string) (stats types.RoomStats, err error)
+	GetLinkQuality(token string) (quality []types.LinkQuality, err error)
+	PingUsers() (messages []types.Message)
+	SetGameSettings(token string, settings types.GameSettings) (err error)
+	GetGameSettings(token string) (settings types.GameSettings, err error)
+	Tick(now time.Time) (messages []types.Message)
+	JoinGroup(token string, name string) (err error)
+	LeaveGroup(token string, name string) (err error)
+	GetGroups(token string) (groups []types.Group, err error)
+	ListRooms(token string, request types.ListRoomsRequest) (rooms types.RoomList, err error)
+	GetRoom(token string, room types.RoomKey) (info types.RoomInfo, err error)
+	CloseRoom(token string, request types.CloseRoomRequest) (err error)
+	UpdateRoom(token string, request types.UpdateRoomRequest) (err error)
+	KickUser(token string, request types.KickUserRequest) (err error)
+	BanUser(token string, request types.BanUserRequest) (err error)
+	GetRoomBans(token string, room types.RoomKey) (bans []types.Ban, err error)
+	GetGameBans(token string) (bans []types.Ban, err error)
+	SetRoomMetadata(token string, request types.SetRoomMetadataRequest) (version uint64, err error)
+}
+
+// RoomGuardTTL is how long a room created with NoAutoCreate is kept from
+// being created again by handshakes after its last join.
+const RoomGuardTTL = 24 * time.Hour
+
+// TickResolution is how often Tick should be called; it bounds the tick rate
+// of a room.
+const (
+	TickResolution = time.Millisecond
+	MaxTickRate    = int(time.Second / TickResolution)
+)
+
+type Config struct {
+	MTU               int
+	MaxFragmentedSize int
+	IdleTimeout       time.Duration
+	LegacyIdleTimeout time.Duration
+	PingInterval      time.Duration
 }
 
 type service struct {
 	maxConnections uint64
+	config         Config
+
+	storage  memoryDB.IMemoryDB
+	sessions sync.Map
+	games    sync.Map
+	ticking  sync.Map
+	bans     sync.Map
 
-	storage memoryDB.IMemoryDB
+	outboxMu sync.Mutex
+	outbox   []types.Message
 
 	token  tokengenerator.TokenGenerator
+	cookie *cookie.Generator
 	logger zerolog.Logger
 }
 
@@ -45,6 +103,29 @@ func (s *service) CreateRoom(token string, room types.CreateRoomRequest) error {
 		return err
 	}
 
+	if room.TickRate < 0 || room.TickRate > MaxTickRate {
+		return errors.ErrBadTickRate
+	}
+	switch room.Mode {
+	case types.RoomModeRelay:
+	case types.RoomModeLockstep, types.RoomModeRollback:
+		if room.TickRate != 0 {
+			return errors.ErrBadRoomMode
+		}
+	default:
+		return errors.ErrBadRoomMode
+	}
+	if room.InputHistory < 0 || room.InputHistory > rollback.MaxHistory {
+		return errors.ErrBadInputHistory
+	}
+	interestConfig := interest.Config(room.Interest)
+	if room.Interest != (types.Interest{}) && !interestConfig.Valid() {
+		return errors.ErrBadInterest
+	}
+	if room.MaxUsers < 0 {
+		return errors.ErrBadMaxUsers
+	}
+
 	roomKey := utils.GenerateRoomKey(clientInfo)
 
 	_, ok := s.storage.GetData(roomKey)
@@ -52,51 +133,188 @@ func (s *service) CreateRoom(token string, room types.CreateRoomRequest) error {
 		return errors.ErrRoomIsExists
 	}
 
-	s.setRoom(clientInfo, &types.Room{
-		GameID: clientInfo.GameID,
-		RoomID: clientInfo.RoomID,
-	}, room.RoomTTl)
+	now := time.Now()
+	newRoom := &types.Room{
+		GameID:       clientInfo.GameID,
+		RoomID:       clientInfo.RoomID,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+		TickRate:     room.TickRate,
+		NoAutoCreate: room.NoAutoCreate,
+	}
+	newRoom.SetMaxUsers(room.MaxUsers)
+	newRoom.SetAllowedUsers(room.AllowedUsers)
+	newRoom.SetOwner(room.Owner)
+	switch room.Mode {
+	case types.RoomModeLockstep:
+		newRoom.Lockstep = lockstep.NewBuffer(room.InputDelay, room.InputTimeout)
+	case types.RoomModeRollback:
+		newRoom.Rollback = rollback.NewBuffer(room.InputHistory)
+	}
+	if room.Interest.CellSize > 0 {
+		newRoom.Interest = interest.NewGrid(interestConfig)
+	}
+
+	s.setRoom(clientInfo, newRoom, room.RoomTTl)
+	if room.NoAutoCreate {
+		s.storage.SetDataWithTTL(utils.GenerateRoomGuardKey(clientInfo), struct{}{}, room.RoomTTl+RoomGuardTTL)
+	}
 
 	return nil
 }
 
-func (s *service) GetUsersAndMessage(ds connection.DataSender, req []byte) (users []types.User, msg []byte, err error) {
-	clientInfo, room, err := s.getRoom(ds)
-	if err != nil {
-		clientInfo, err = s.setNewUser(ds, req)
-		if err != nil {
-			return nil, nil, err
+// RoutingKey returns the key that picks the worker for a packet: the sender's
+// room when it is known, so that packets of a room are handled in order, and
+// otherwise the session or the sender's address. The packet is not verified
+// here.
+func (s *service) RoutingKey(ds connection.DataSender, packet protocol.Packet) (key string) {
+	var sess *session.Session
+	if packet.Header.Flags.Has(protocol.FlagSession) {
+		data, ok := s.storage.GetData(utils.GenerateSessionKey(packet.SessionID))
+		if !ok {
+			return utils.GenerateSessionKey(packet.SessionID)
 		}
-		return append(users, types.User{Connection: ds}), []byte(clientInfo.UserID.String()), nil
+		sess, _ = data.(*session.Session)
+	} else {
+		sess, _ = s.getSessionByAddress(ds)
 	}
 
-	if len(req) == 454 || len(req) == 343 {
-		return append(users, types.User{Connection: ds}), []byte(clientInfo.UserID.String()), nil
+	if sess == nil {
+		return ds.GetID()
 	}
 
-	usersData := room.GetUser()
-	for _, v := range usersData {
-		if v.ID == clientInfo.UserID &&
-			ds.GetID() == v.Connection.GetID() {
-			continue
-		}
-		users = append(users, *v)
+	return utils.GenerateRoomKey(sess.Info)
+}
+
+func (s *service) RemoveUser(ds connection.DataSender, userID uuid.UUID) (messages []types.Message, err error) {
+	_, room, err := s.getRoom(ds)
+	if err != nil {
+		return nil, err
 	}
 
-	msg = req
+	user, ok := room.GetUserByID(userID)
+	if !ok {
+		return nil, nil
+	}
 
-	return users, msg, err
+	return s.removeUser(messages, room, user), nil
 }
 
-func (s *service) RemoveUser(ds connection.DataSender, userID uuid.UUID) (err error) {
-	_, room, err := s.getRoom(ds)
+// removeUser removes a member from the room, dropping its session, and tells
+// the remaining members.
+func (s *service) removeUser(messages []types.Message, room *types.Room, user *types.User) []types.Message {
+	if user.Session != nil {
+		return s.removeSession(messages, user.Session)
+	}
+
+	return s.leaveRoom(messages, room, user.ID)
+}
+
+// leaveRoom removes a member from the room and tells the remaining members,
+// along with the new owner when the user owned the room and the removal of
+// its metadata keys.
+func (s *service) leaveRoom(messages []types.Message, room *types.Room, userID uuid.UUID) []types.Message {
+	owner, changed := room.RemoveUser(userID)
+	if room.Rollback != nil {
+		room.Rollback.Remove(userID)
+	}
+
+	messages = s.appendUserEvent(messages, room, protocol.TypeUserLeft, userID)
+	if changed {
+		messages = s.appendHostChanged(messages, room, owner)
+	}
+	if version, removed := room.RemoveUserMetadata(userID); len(removed) > 0 {
+		messages = s.appendMetadata(messages, room, version, 0, removed)
+	}
+
+	return messages
+}
+
+// RemoveIdleUsers removes the users whose sessions have received nothing for
+// IdleTimeout and returns the notifications for their rooms. Legacy clients
+// are never pinged, so they get LegacyIdleTimeout instead, and are kept for
+// good when it is zero.
+func (s *service) RemoveIdleUsers() (messages []types.Message) {
+	now := time.Now()
+	deadline := now.Add(-s.config.IdleTimeout)
+	legacyDeadline := now.Add(-s.config.LegacyIdleTimeout)
+
+	s.sessions.Range(func(_, value any) bool {
+		sess := value.(*session.Session)
+		idle := sess.LastSeen().Before(deadline)
+		if sess.Legacy {
+			idle = s.config.LegacyIdleTimeout > 0 && sess.LastSeen().Before(legacyDeadline)
+		}
+		if idle {
+			s.logger.Debug().Str("userID", sess.Info.UserID.String()).Msg("session idle")
+			messages = s.removeSession(messages, sess)
+		}
+		return true
+	})
+
+	return messages
+}
+
+// removeSession drops the session and, unless the user has joined again with
+// another session, removes the user from the room.
+func (s *service) removeSession(messages []types.Message, sess *session.Session) []types.Message {
+	s.closeSession(sess)
+
+	room, err := s.getRoomByClientInfo(sess.Info)
 	if err != nil {
-		return err
+		return messages
+	}
+
+	user, ok := room.GetUserByID(sess.Info.UserID)
+	if !ok || user.Session != sess {
+		return messages
 	}
+	return s.leaveRoom(messages, room, sess.Info.UserID)
+}
 
-	room.RemoveUser(userID)
+// closeSession drops the session without touching the room.
+func (s *service) closeSession(sess *session.Session) {
+	s.sessions.Delete(sess.ID)
+	if sess.Reliable != nil {
+		sess.Reliable.Close()
+	}
+	s.storage.Remove(utils.GenerateSessionKey(sess.ID))
+	if conn := sess.Connection(); conn != nil {
+		if current, ok := s.getSessionByAddress(conn); ok && current == sess {
+			s.storage.Remove(conn.GetID())
+		}
+	}
+}
 
-	return nil
+// appendUserEvent tells the other members of the room that a user joined or
+// left. The notification is reliable; legacy clients do not receive it.
+func (s *service) appendUserEvent(messages []types.Message, room *types.Room, eventType protocol.MessageType, userID uuid.UUID) []types.Message {
+	var users []types.User
+	for _, user := range room.GetUser() {
+		if user.ID == userID || user.Legacy {
+			continue
+		}
+		users = append(users, *user)
+	}
+	if len(users) == 0 {
+		return messages
+	}
+
+	event := protocol.NewPacket(eventType, 0, userID[:])
+	event.Header.Flags |= protocol.FlagReliable
+
+	return append(messages, types.Message{Users: users, Packet: event})
+}
+
+// send queues messages produced outside of the UDP workers, such as by the
+// JSON-RPC API. They are written on the next call to Tick.
+func (s *service) send(messages []types.Message) {
+	if len(messages) == 0 {
+		return
+	}
+	s.outboxMu.Lock()
+	defer s.outboxMu.Unlock()
+	s.outbox = append(s.outbox, messages...)
 }
 
 func (s *service) GetDeletedRooms(token string, ids []types.GetDeletedRooms) (deletedIds []types.GetDeletedRooms, err error) {
@@ -126,66 +344,335 @@ func (s *service) GetDeletedRooms(token string, ids []types.GetDeletedRooms) (de
 	return deletedIds, nil
 }
 
-func (s *service) setNewUser(ds connection.DataSender, req []byte) (clientInfo *tokentype.Info, err error) {
-	token := string(req)
+func (s *service) GetRoomStats(token string) (stats types.RoomStats, err error) {
+	info, err := s.token.ParseToken(token)
+	if err != nil {
+		return stats, err
+	}
 
+	room, err := s.getRoomByClientInfo(info)
+	if err != nil {
+		return stats, err
+	}
+
+	stats.Users = len(room.GetUser())
+	stats.Reliable = room.ReliableStats()
+
+	return stats, nil
+}
+
+func (s *service) GetLinkQuality(token string) (quality []types.LinkQuality, err error) {
 	info, err := s.token.ParseToken(token)
 	if err != nil {
-		return clientInfo, errors.ErrNewConnectionMastGetToken
+		return nil, err
+	}
+
+	room, err := s.getRoomByClientInfo(info)
+	if err != nil {
+		return nil, err
 	}
-	clientInfo = &info
-	s.storage.SetData(ds.GetID(), info)
-	roomKey := utils.GenerateRoomKey(info)
 
-	var room *types.Room
-	roomData, ok := s.storage.GetData(roomKey)
+	for _, user := range room.GetUser() {
+		if user.Session == nil {
+			continue
+		}
+		stats := user.Session.Link.Stats()
+		quality = append(quality, types.LinkQuality{
+			UserID:   user.ID,
+			RTT:      stats.RTT,
+			Jitter:   stats.Jitter,
+			Loss:     stats.Loss,
+			Pings:    stats.Pings,
+			Pongs:    stats.Pongs,
+			LastSeen: user.Session.LastSeen(),
+		})
+	}
+
+	return quality, nil
+}
+
+// PingUsers returns a ping for every session that has not been pinged for
+// PingInterval. Legacy clients cannot answer pings and are skipped.
+func (s *service) PingUsers() (messages []types.Message) {
+	now := time.Now()
+
+	s.sessions.Range(func(_, value any) bool {
+		sess := value.(*session.Session)
+		if sess.Legacy {
+			return true
+		}
+
+		id, ok := sess.Link.Ping(now, s.config.PingInterval)
+		if !ok {
+			return true
+		}
+
+		messages = append(messages, types.Message{
+			Users:  []types.User{{ID: sess.Info.UserID, Connection: sess.Connection(), Session: sess}},
+			Packet: protocol.NewPacket(protocol.TypePing, id, protocol.PingPayload(id)),
+		})
+		return true
+	})
+
+	return messages
+}
+
+// SetGameSettings changes the settings of the token's game. Only backend
+// tokens, which carry no user, may change them.
+func (s *service) SetGameSettings(token string, settings types.GameSettings) (err error) {
+	info, err := s.token.ParseToken(token)
+	if err != nil {
+		return err
+	}
+	if info.UserID != uuid.Nil {
+		return errors.ErrBackendTokenRequired
+	}
+
+	s.games.Store(info.GameID, settings)
+
+	return nil
+}
+
+func (s *service) GetGameSettings(token string) (settings types.GameSettings, err error) {
+	info, err := s.token.ParseToken(token)
+	if err != nil {
+		return settings, err
+	}
+
+	return s.getGameSettings(info.GameID), nil
+}
+
+func (s *service) getGameSettings(gameID uuid.UUID) (settings types.GameSettings) {
+	data, ok := s.games.Load(gameID)
 	if !ok {
+		return settings
+	}
+	return data.(types.GameSettings)
+}
+
+// setNewUser joins the token's user to its room and returns the notifications
+// for the other members when the user was not in the room yet. A missing room
+// is created unless it was created with NoAutoCreate. A non-nil
+// peerKey is the client's public key of an encrypted handshake.
+func (s *service) setNewUser(ds connection.DataSender, req []byte, peerKey []byte, legacy bool) (sess *session.Session, messages []types.Message, err error) {
+	token := string(req)
+
+	info, err := s.token.ParseToken(token)
+	if err != nil {
+		return nil, nil, errors.ErrNewConnectionMastGetToken
+	}
+	if info.UserID == uuid.Nil {
+		return nil, nil, errors.ErrTokenForbidden
+	}
+
+	encrypted := peerKey != nil
+	if !encrypted && s.getGameSettings(info.GameID).Encryption {
+		return nil, nil, errors.ErrEncryptionRequired
+	}
+	if s.banned(info) {
+		return nil, nil, errors.ErrBanned
+	}
+
+	roomKey := utils.GenerateRoomKey(info)
+	room, err := s.getRoomByClientInfo(info)
+	if err == errors.ErrRoomNotFound {
+		if _, guarded := s.storage.GetData(utils.GenerateRoomGuardKey(info)); guarded {
+			return nil, nil, errors.ErrRoomNotFound
+		}
+		now := time.Now()
 		room = &types.Room{
-			GameID: clientInfo.GameID,
-			RoomID: clientInfo.RoomID,
+			GameID:    info.GameID,
+			RoomID:    info.RoomID,
+			CreatedAt: now,
+			UpdatedAt: now,
 		}
-		s.setRoom(*clientInfo, room, 0)
-	} else {
-		room, ok = roomData.(*types.Room)
-		if !ok {
-			return clientInfo, errors.ErrRoomBadValue
+		s.setRoom(info, room, 0)
+	} else if err != nil {
+		return nil, nil, err
+	}
+
+	sess, ok := s.getSessionByAddress(ds)
+	if !ok || sess.Info.UserID != info.UserID || utils.GenerateRoomKey(sess.Info) != utils.GenerateRoomKey(info) || sess.Encrypted() != encrypted {
+		sess, err = s.newSession(info, ds, legacy)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	if encrypted {
+		err = sess.Encrypt(peerKey)
+		if err != nil {
+			return nil, nil, err
 		}
 	}
+	sess.Touch()
+	s.sessions.Store(sess.ID, sess)
+	s.storage.SetData(ds.GetID(), sess)
+	s.storage.SetData(utils.GenerateSessionKey(sess.ID), sess)
 
-	room.SetUser(&types.User{
+	if sess.Reliable == nil {
+		sess.Reliable = reliable.NewChannel(&room.Reliable)
+	}
+
+	user := &types.User{
 		ID:         info.UserID,
 		Connection: ds,
-	})
+		Legacy:     legacy,
+		Session:    sess,
+	}
+	joined, err := room.Join(user)
+	if err != nil {
+		s.closeSession(sess)
+		return nil, nil, err
+	}
+	if joined {
+		messages = s.appendUserEvent(messages, room, protocol.TypeUserJoined, info.UserID)
+		messages = s.appendGroups(messages, room, *user)
+		messages = s.appendHost(messages, room, *user)
+		messages = s.appendMetadataSnapshot(messages, room, *user)
+	}
+	if room.NoAutoCreate {
+		s.storage.SetDataWithTTL(utils.GenerateRoomGuardKey(info), struct{}{}, RoomGuardTTL)
+	}
+	if room.TickRate > 0 || room.Lockstep != nil {
+		s.ticking.Store(roomKey, room)
+	}
 
 	s.storage.AddConnection(token)
 
-	return clientInfo, nil
+	return sess, messages, nil
 }
 
-func (s *service) getRoom(ds connection.DataSender) (clientInfo *tokentype.Info, room *types.Room, err error) {
-	client, ok := s.storage.GetData(ds.GetID())
+func (s *service) newSession(info tokentype.Info, ds connection.DataSender, legacy bool) (sess *session.Session, err error) {
+	sess, err = session.NewSession(info, ds, legacy)
+	if err != nil {
+		return nil, err
+	}
+	sess.MTU = s.config.MTU
+	sess.Fragments = fragment.NewReassembler(s.config.MaxFragmentedSize)
+	sess.Link = link.NewLink()
+	return sess, nil
+}
+
+// getSession finds the sender's session and returns the packet decrypted when
+// the session is encrypted. Packets carrying a session ID are verified with
+// the session key and move the session to the sender's address, keeping the
+// room membership when the client's address changes. A replayed packet
+// returns its session along with ErrPacketReplayed for reack.
+func (s *service) getSession(ds connection.DataSender, packet protocol.Packet) (sess *session.Session, opened protocol.Packet, err error) {
+	if !packet.Header.Flags.Has(protocol.FlagSession) {
+		sess, ok := s.getSessionByAddress(ds)
+		if !ok {
+			return nil, packet, errors.ErrUserNotFound
+		}
+		sess.Touch()
+		opened, err = sess.Open(packet)
+		if err == errors.ErrPacketReplayed {
+			return sess, opened, err
+		}
+		if err != nil {
+			return nil, packet, err
+		}
+		return sess, opened, nil
+	}
+
+	data, ok := s.storage.GetData(utils.GenerateSessionKey(packet.SessionID))
 	if !ok {
-		return nil, nil, errors.ErrUserNotFound
+		return nil, packet, errors.ErrSessionNotFound
 	}
 
-	info, ok := client.(tokentype.Info)
+	sess, ok = data.(*session.Session)
 	if !ok {
-		return nil, nil, errors.ErrUserBadValue
+		return nil, packet, errors.ErrUserBadValue
 	}
 
-	roomKey := utils.GenerateRoomKey(info)
+	if !packet.Verify(sess.Key) {
+		return nil, packet, errors.ErrSessionBadMAC
+	}
 
-	roomData, ok := s.storage.GetData(roomKey)
+	opened, err = sess.Open(packet)
+	if err == errors.ErrPacketReplayed {
+		return sess, opened, err
+	}
+	if err != nil {
+		return nil, packet, err
+	}
+
+	old, migrated, err := sess.Observe(ds, packet.Header.Sequence)
+	if err != nil {
+		return nil, packet, err
+	}
+	sess.Touch()
+	if migrated {
+		err = s.migrateSession(sess, old, ds)
+		if err != nil {
+			return nil, packet, err
+		}
+	} else {
+		s.keepAddress(sess, ds)
+	}
+
+	return sess, opened, nil
+}
+
+// keepAddress keeps the address of a verified packet mapped to its session,
+// which only session lookups would otherwise keep alive, so that unsigned
+// packets and address lookups still find the session after the storage TTL.
+func (s *service) keepAddress(sess *session.Session, ds connection.DataSender) {
+	if data, ok := s.storage.GetData(ds.GetID()); !ok || data != sess {
+		s.storage.SetData(ds.GetID(), sess)
+	}
+}
+
+func (s *service) migrateSession(sess *session.Session, old, ds connection.DataSender) (err error) {
+	if old != nil {
+		s.storage.Remove(old.GetID())
+	}
+	s.storage.SetData(ds.GetID(), sess)
+
+	room, err := s.getRoomByClientInfo(sess.Info)
+	if err != nil {
+		return err
+	}
+
+	room.SetUser(&types.User{
+		ID:         sess.Info.UserID,
+		Connection: ds,
+		Legacy:     sess.Legacy,
+		Session:    sess,
+	})
+
+	s.logger.Debug().Str("userID", sess.Info.UserID.String()).Str("addr", ds.GetID()).Msg("session migrated")
+
+	return nil
+}
+
+func (s *service) getSessionByAddress(ds connection.DataSender) (sess *session.Session, ok bool) {
+	data, ok := s.storage.GetData(ds.GetID())
 	if !ok {
-		return nil, nil, errors.ErrRoomNotFound
+		return nil, false
 	}
 
-	room, ok = roomData.(*types.Room)
+	sess, ok = data.(*session.Session)
+	return sess, ok
+}
+
+func (s *service) getRoom(ds connection.DataSender) (clientInfo *tokentype.Info, room *types.Room, err error) {
+	client, ok := s.storage.GetData(ds.GetID())
 	if !ok {
-		return nil, nil, errors.ErrRoomBadValue
+		return nil, nil, errors.ErrUserNotFound
+	}
+
+	sess, ok := client.(*session.Session)
+	if !ok {
+		return nil, nil, errors.ErrUserBadValue
+	}
+
+	room, err = s.getRoomByClientInfo(sess.Info)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	return &info, room, nil
+	return &sess.Info, room, nil
 }
 
 func (s *service) getRoomByClientInfo(clientInfo tokentype.Info) (room *types.Room, err error) {
@@ -213,11 +700,13 @@ func (s *service) setRoom(clientInfo tokentype.Info, room *types.Room, ttl time.
 	s.storage.SetData(roomKey, room)
 }
 
-func NewService(token tokengenerator.TokenGenerator, storage memoryDB.IMemoryDB, logger zerolog.Logger) Service {
+func NewService(token tokengenerator.TokenGenerator, cookie *cookie.Generator, storage memoryDB.IMemoryDB, config Config, logger zerolog.Logger) Service {
 	srv := &service{
 		maxConnections: uint64(types.CountConnectionsMAX()),
+		config:         config,
 		storage:        storage,
 		token:          token,
+		cookie:         cookie,
 		logger:         logger,
 	}
 	return srv
@@ -0,0 +1,174 @@
+package service
+
+import (
+	"github.com/ascenmmo/udp-server/pkg/api/types"
+	"github.com/ascenmmo/udp-server/pkg/errors"
+	"github.com/ascenmmo/udp-server/pkg/protocol"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestSessionMigration(t *testing.T) {
+	room := newTestRoom(t, 1200)
+	_, bID := room.join("b")
+
+	old := &testSender{id: "old"}
+	userID := uuid.New()
+	sess, _, err := room.service.setNewUser(old, []byte(room.token(userID)), nil, false)
+	assert.NoError(t, err)
+
+	signed := func(sequence uint32, payload string) protocol.Packet {
+		packet := protocol.NewPacket(protocol.TypeData, sequence, []byte(payload))
+		packet.SessionID = sess.ID
+		decoded, err := protocol.Decode(packet.Sign(sess.Key))
+		assert.NoError(t, err)
+		return decoded
+	}
+
+	moved := &testSender{id: "new"}
+	messages, err := room.service.relay(moved, signed(2, "moved"))
+	assert.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{bID}, recipients(messages))
+	assert.Equal(t, "new", sess.Connection().GetID())
+	_, ok := room.service.getSessionByAddress(old)
+	assert.False(t, ok)
+	found, ok := room.service.getSessionByAddress(moved)
+	assert.True(t, ok)
+	assert.Same(t, sess, found)
+	members, err := room.service.getRoomByClientInfo(sess.Info)
+	assert.NoError(t, err)
+	user, ok := members.GetUserByID(userID)
+	assert.True(t, ok)
+	assert.Equal(t, "new", user.Connection.GetID(), "the room sends to the new address")
+
+	_, err = room.service.relay(old, signed(1, "stale"))
+	assert.Equal(t, errors.ErrPacketReplayed, err, "a stale packet from the old address is rejected")
+	assert.Equal(t, "new", sess.Connection().GetID())
+
+	_, err = room.service.relay(&testSender{id: "unknown"}, protocol.NewPacket(protocol.TypeData, 3, []byte("spoofed")))
+	assert.Equal(t, errors.ErrUserNotFound, err, "unsigned packets from unknown addresses do not migrate")
+	assert.Equal(t, "new", sess.Connection().GetID())
+
+	packet := signed(4, "forged")
+	packet.MAC[0] ^= 1
+	_, err = room.service.relay(&testSender{id: "forged"}, packet)
+	assert.Equal(t, errors.ErrSessionBadMAC, err)
+	assert.Equal(t, "new", sess.Connection().GetID())
+}
+
+func TestSignedPacketsKeepAddress(t *testing.T) {
+	room := newTestRoom(t, 1200)
+	_, bID := room.join("b")
+
+	a := &testSender{id: "a"}
+	sess, _, err := room.service.setNewUser(a, []byte(room.token(uuid.New())), nil, false)
+	assert.NoError(t, err)
+	signed := func(sequence uint32) protocol.Packet {
+		packet := protocol.NewPacket(protocol.TypeData, sequence, []byte("signed"))
+		packet.SessionID = sess.ID
+		decoded, err := protocol.Decode(packet.Sign(sess.Key))
+		assert.NoError(t, err)
+		return decoded
+	}
+
+	room.service.storage.SetDataWithTTL(a.GetID(), sess, -time.Hour)
+	_, err = room.service.relay(a, signed(1))
+	assert.NoError(t, err)
+	expiresAt, ok := room.service.storage.ExpiresAt(a.GetID())
+	assert.True(t, ok)
+	assert.True(t, expiresAt.After(time.Now()), "a verified packet keeps the address alive")
+
+	room.service.storage.Remove(a.GetID())
+	_, err = room.service.relay(a, signed(2))
+	assert.NoError(t, err)
+	found, ok := room.service.getSessionByAddress(a)
+	assert.True(t, ok, "an address that expired while the session was used is mapped again")
+	assert.Same(t, sess, found)
+
+	messages, err := room.service.relay(a, protocol.NewPacket(protocol.TypeData, 3, []byte("unsigned")))
+	assert.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{bID}, recipients(messages))
+}
+
+func userEvents(t *testing.T, messages []types.Message, kind protocol.MessageType) (events map[uuid.UUID][]uuid.UUID) {
+	events = make(map[uuid.UUID][]uuid.UUID)
+	for _, msg := range messages {
+		if msg.Packet.Header.Type != kind {
+			continue
+		}
+		assert.True(t, msg.Packet.IsReliable())
+		for _, user := range msg.Users {
+			events[user.ID] = append(events[user.ID], uuid.UUID(msg.Packet.Payload))
+		}
+	}
+	return events
+}
+
+func TestUserEvents(t *testing.T) {
+	room := newTestRoom(t, 1200)
+	_, aID := room.join("a")
+	legacyID := uuid.New()
+	_, _, err := room.service.setNewUser(&testSender{id: "legacy"}, []byte(room.token(legacyID)), nil, true)
+	assert.NoError(t, err)
+
+	b := &testSender{id: "b"}
+	bID := uuid.New()
+	_, messages, err := room.service.setNewUser(b, []byte(room.token(bID)), nil, false)
+	assert.NoError(t, err)
+	assert.Equal(t, map[uuid.UUID][]uuid.UUID{aID: {bID}}, userEvents(t, messages, protocol.TypeUserJoined), "legacy clients are not told")
+
+	messages, err = room.service.GetUsersAndMessages(b, protocol.NewPacket(protocol.TypeLeave, 2, nil))
+	assert.NoError(t, err)
+	assert.Equal(t, map[uuid.UUID][]uuid.UUID{aID: {bID}}, userEvents(t, messages, protocol.TypeUserLeft))
+	_, ok := room.service.getSessionByAddress(b)
+	assert.False(t, ok)
+}
+
+func TestRemoveIdleUsers(t *testing.T) {
+	room := newTestRoom(t, 1200)
+	room.service.config.IdleTimeout = 20 * time.Millisecond
+	room.service.config.LegacyIdleTimeout = time.Hour
+
+	a, aID := room.join("a")
+	_, bID := room.join("b")
+	legacy := &testSender{id: "legacy"}
+	_, _, err := room.service.setNewUser(legacy, []byte(room.token(uuid.New())), nil, true)
+	assert.NoError(t, err)
+
+	time.Sleep(30 * time.Millisecond)
+	sess, _ := room.service.getSessionByAddress(a)
+	sess.Touch()
+
+	messages := room.service.RemoveIdleUsers()
+	assert.Equal(t, map[uuid.UUID][]uuid.UUID{aID: {bID}}, userEvents(t, messages, protocol.TypeUserLeft), "idle users are removed and their room is told")
+	_, ok := room.service.getSessionByAddress(a)
+	assert.True(t, ok)
+	_, ok = room.service.getSessionByAddress(legacy)
+	assert.True(t, ok, "legacy clients are not pinged and get a longer timeout")
+
+	room.service.config.LegacyIdleTimeout = 0
+	assert.Empty(t, room.service.RemoveIdleUsers())
+	_, ok = room.service.getSessionByAddress(legacy)
+	assert.True(t, ok, "legacy clients are kept when the timeout is 0")
+
+	room.service.config.LegacyIdleTimeout = 20 * time.Millisecond
+	room.service.RemoveIdleUsers()
+	_, ok = room.service.getSessionByAddress(legacy)
+	assert.False(t, ok)
+}
+
+func TestGameSettings(t *testing.T) {
+	room := newTestRoom(t, 1200)
+	player := room.token(uuid.New())
+
+	assert.Equal(t, errors.ErrBackendTokenRequired, room.service.SetGameSettings(player, types.GameSettings{}), "players do not change the settings of their game")
+	assert.NoError(t, room.service.SetGameSettings(room.token(uuid.Nil), types.GameSettings{Encryption: true}))
+
+	settings, err := room.service.GetGameSettings(player)
+	assert.NoError(t, err)
+	assert.True(t, settings.Encryption)
+	_, _, err = room.service.setNewUser(&testSender{id: "a"}, []byte(player), nil, false)
+	assert.Equal(t, errors.ErrEncryptionRequired, err)
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	tokentype "github.com/ascenmmo/token-generator/token_type"
+	"github.com/ascenmmo/udp-server/internal/connection"
+	"github.com/ascenmmo/udp-server/pkg/api/types"
+	"github.com/ascenmmo/udp-server/pkg/errors"
+	"github.com/ascenmmo/udp-server/pkg/protocol"
+	"github.com/google/uuid"
+	"slices"
+)
+
+// targetUsers resolves the target of a packet against the room. Only members
+// of the room can be targeted; a group targets its members, the sender
+// included when it belongs to the group.
+func (s *service) targetUsers(ds connection.DataSender, clientInfo *tokentype.Info, room *types.Room, target protocol.Target) (users []types.User, err error) {
+	switch target.Kind {
+	case protocol.TargetOthers:
+		return s.roomUsersExceptSender(ds, clientInfo, room), nil
+	case protocol.TargetAll:
+		for _, user := range room.GetUser() {
+			users = append(users, *user)
+		}
+		return users, nil
+	case protocol.TargetUsers:
+		for _, id := range target.Users {
+			user, ok := room.GetUserByID(id)
+			if !ok {
+				return nil, errors.ErrTargetNotMember
+			}
+			if !slices.ContainsFunc(users, func(u types.User) bool { return u.ID == id }) {
+				users = append(users, *user)
+			}
+		}
+		return users, nil
+	case protocol.TargetGroup:
+		members, ok := room.GroupMembers(target.Group)
+		if !ok {
+			return nil, errors.ErrGroupNotFound
+		}
+		for _, id := range members {
+			if user, ok := room.GetUserByID(id); ok {
+				users = append(users, *user)
+			}
+		}
+		return users, nil
+	case protocol.TargetOwner:
+		user, ok := room.GetUserByID(room.Owner())
+		if !ok {
+			return nil, errors.ErrOwnerNotFound
+		}
+		return []types.User{*user}, nil
+	default:
+		return nil, errors.ErrPacketBadTarget
+	}
+}
+
+// isFiltered reports whether the area of interest of the room applies to a
+// packet. Only packets sent to the whole room are filtered.
+func (s *service) isFiltered(room *types.Room, packet protocol.Packet) bool {
+	if room.Interest == nil || packet.Header.Flags.Has(protocol.FlagBypass) {
+		return false
+	}
+	return packet.Target.Kind == protocol.TargetOthers || packet.Target.Kind == protocol.TargetAll
+}
+
+// filterInterest keeps the users in the area of interest of sender.
+func (s *service) filterInterest(room *types.Room, sender uuid.UUID, users []types.User) []types.User {
+	ids := make([]uuid.UUID, len(users))
+	for i, user := range users {
+		ids[i] = user.ID
+	}
+
+	kept := room.Interest.Filter(sender, ids)
+	filtered := users[:0]
+	for _, user := range users {
+		if len(kept) > 0 && kept[0] == user.ID {
+			filtered = append(filtered, user)
+			kept = kept[1:]
+		}
+	}
+	return filtered
+}
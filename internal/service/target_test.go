@@ -0,0 +1,82 @@
+package service
+
+import (
+	"github.com/ascenmmo/udp-server/pkg/api/types"
+	"github.com/ascenmmo/udp-server/pkg/errors"
+	"github.com/ascenmmo/udp-server/pkg/protocol"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func recipients(messages []types.Message) (users []uuid.UUID) {
+	for _, msg := range messages {
+		if msg.Packet.Header.Type != protocol.TypeData {
+			continue
+		}
+		for _, user := range msg.Users {
+			users = append(users, user.ID)
+		}
+	}
+	return users
+}
+
+func TestRelayTarget(t *testing.T) {
+	room := newTestRoom(t, 1200)
+	assert.NoError(t, room.service.CreateRoom(room.token(uuid.New()), types.CreateRoomRequest{}))
+
+	a, aID := room.join("a")
+	_, bID := room.join("b")
+	_, cID := room.join("c")
+
+	relay := func(target protocol.Target) ([]uuid.UUID, error) {
+		packet := protocol.NewPacket(protocol.TypeData, 1, []byte("payload"))
+		packet.Header.Flags |= protocol.FlagTarget
+		packet.Target = target
+		messages, err := room.service.relay(a, packet)
+		return recipients(messages), err
+	}
+
+	users, err := relay(protocol.Target{})
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []uuid.UUID{bID, cID}, users)
+
+	users, err = relay(protocol.Target{Kind: protocol.TargetAll})
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []uuid.UUID{aID, bID, cID}, users)
+
+	users, err = relay(protocol.Target{Kind: protocol.TargetUsers, Users: []uuid.UUID{cID, cID}})
+	assert.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{cID}, users)
+
+	_, err = relay(protocol.Target{Kind: protocol.TargetUsers, Users: []uuid.UUID{bID, uuid.New()}})
+	assert.Equal(t, errors.ErrTargetNotMember, err)
+
+	_, err = relay(protocol.Target{Kind: protocol.TargetGroup, Group: "red"})
+	assert.Equal(t, errors.ErrGroupNotFound, err)
+}
+
+func TestTickHoldsTarget(t *testing.T) {
+	room := newTestRoom(t, 1200)
+	assert.NoError(t, room.service.CreateRoom(room.token(uuid.New()), types.CreateRoomRequest{TickRate: 20}))
+
+	a, aID := room.join("a")
+	_, bID := room.join("b")
+	_, cID := room.join("c")
+
+	packet := protocol.NewPacket(protocol.TypeData, 1, []byte("whisper"))
+	packet.Header.Flags |= protocol.FlagTarget
+	packet.Target = protocol.Target{Kind: protocol.TargetUsers, Users: []uuid.UUID{bID}}
+	_, err := room.service.relay(a, packet)
+	assert.NoError(t, err)
+	room.send(a, []byte("all"), false)
+
+	messages := room.service.Tick(time.Now())
+	entries, _ := bundlesFor(t, messages, aID)
+	assert.Empty(t, entries)
+	entries, _ = bundlesFor(t, messages, bID)
+	assert.Equal(t, []string{"whisper", "all"}, entries)
+	entries, _ = bundlesFor(t, messages, cID)
+	assert.Equal(t, []string{"all"}, entries)
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"github.com/ascenmmo/udp-server/pkg/api/types"
+	"github.com/ascenmmo/udp-server/pkg/protocol"
+	"time"
+)
+
+// Tick returns the bundles of the tick-mode rooms whose tick is due at now.
+// Every member receives the messages of the other members held since the last
+// tick, packed into as few bundle packets as fit the MTU. Lockstep rooms send
+// the frames whose deadline has passed. Rooms without members stop ticking
+// until someone joins again, and rooms that expired from the storage for
+// good. The messages queued by send go first.
+func (s *service) Tick(now time.Time) (messages []types.Message) {
+	s.outboxMu.Lock()
+	messages, s.outbox = s.outbox, nil
+	s.outboxMu.Unlock()
+
+	s.ticking.Range(func(key, value any) bool {
+		room := value.(*types.Room)
+
+		// ExpiresAt does not keep the room alive, unlike getRoomByClientInfo.
+		if _, ok := s.storage.ExpiresAt(key.(string)); !ok {
+			s.ticking.CompareAndDelete(key, room)
+			return true
+		}
+
+		users := room.GetUser()
+		if len(users) == 0 {
+			s.ticking.Delete(key)
+			if len(room.GetUser()) > 0 {
+				s.ticking.Store(key, room)
+			}
+			return true
+		}
+
+		if room.Lockstep != nil {
+			messages = s.closeFrames(messages, room, now)
+			return true
+		}
+
+		tick, held, due := room.Tick(now)
+		if !due || len(held) == 0 {
+			return true
+		}
+
+		for _, user := range users {
+			messages = s.appendBundles(messages, room, *user, tick, held)
+		}
+		return true
+	})
+
+	return messages
+}
+
+// appendBundles packs the held messages addressed to user into bundles
+// for user, keeping their order. A message too large for a bundle of its own
+// is sent as a data packet and fragmented. Legacy clients receive every
+// message as is.
+func (s *service) appendBundles(messages []types.Message, room *types.Room, user types.User, tick uint32, held []types.HeldMessage) []types.Message {
+	var bundle []byte
+	var reliable bool
+	flush := func() {
+		if bundle == nil {
+			return
+		}
+		packet := protocol.NewPacket(protocol.TypeBundle, tick, bundle)
+		if reliable {
+			packet.Header.Flags |= protocol.FlagReliable
+		}
+		messages = append(messages, types.Message{Users: []types.User{user}, Packet: packet})
+		bundle, reliable = nil, false
+	}
+
+	limit := s.bundleLimit(user, tick)
+	for _, msg := range held {
+		if !msg.IsFor(user.ID) {
+			continue
+		}
+		room.Traffic.Out(1, len(msg.Payload))
+
+		size := protocol.BundleLengthSize + len(msg.Payload)
+		if user.Legacy || size > limit || len(msg.Payload) > protocol.MaxBundleEntry {
+			flush()
+			packet := protocol.NewPacket(protocol.TypeData, tick, msg.Payload)
+			if msg.Reliable {
+				packet.Header.Flags |= protocol.FlagReliable
+			}
+			messages = append(messages, types.Message{Users: []types.User{user}, Packet: packet})
+			continue
+		}
+
+		if len(bundle)+size > limit {
+			flush()
+		}
+		bundle = protocol.AppendBundle(bundle, msg.Payload)
+		reliable = reliable || msg.Reliable
+	}
+	flush()
+
+	return messages
+}
+
+// bundleLimit returns the payload size of a bundle that is sent to user
+// without fragmentation, leaving room for the reliable sequence number.
+func (s *service) bundleLimit(user types.User, tick uint32) int {
+	packet := protocol.NewPacket(protocol.TypeBundle, tick, nil)
+	packet.Header.Flags |= protocol.FlagReliable
+	if user.Session != nil {
+		return user.Session.MaxPayload(packet)
+	}
+	return s.config.MTU - packet.Size()
+}
@@ -0,0 +1,192 @@
+package service
+
+import (
+	"context"
+	tokengenerator "github.com/ascenmmo/token-generator/token_generator"
+	tokentype "github.com/ascenmmo/token-generator/token_type"
+	"github.com/ascenmmo/udp-server/internal/cookie"
+	memoryDB "github.com/ascenmmo/udp-server/internal/storage"
+	"github.com/ascenmmo/udp-server/internal/utils"
+	"github.com/ascenmmo/udp-server/pkg/api/types"
+	"github.com/ascenmmo/udp-server/pkg/protocol"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+type testSender struct {
+	id string
+}
+
+func (t *testSender) GetID() string {
+	return t.id
+}
+
+func (t *testSender) Write([]byte) error {
+	return nil
+}
+
+type testRoom struct {
+	t       *testing.T
+	service *service
+	tokens  tokengenerator.TokenGenerator
+	info    tokentype.Info
+}
+
+func newTestRoom(t *testing.T, mtu int) *testRoom {
+	tokens, err := tokengenerator.NewTokenGenerator("_remember_token_must_be_32_bytes")
+	assert.NoError(t, err)
+	cookies, err := cookie.NewGenerator()
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	srv := NewService(tokens, cookies, memoryDB.NewMemoryDb(ctx, 10), Config{
+		MTU:               mtu,
+		MaxFragmentedSize: 1 << 20,
+		IdleTimeout:       time.Minute,
+		PingInterval:      time.Minute,
+	}, zerolog.Nop()).(*service)
+
+	return &testRoom{
+		t:       t,
+		service: srv,
+		tokens:  tokens,
+		info:    tokentype.Info{GameID: uuid.New(), RoomID: uuid.New(), TTL: time.Minute},
+	}
+}
+
+func (r *testRoom) token(userID uuid.UUID) string {
+	info := r.info
+	info.UserID = userID
+	token, err := r.tokens.GenerateToken(info, tokengenerator.JWT)
+	assert.NoError(r.t, err)
+	return token
+}
+
+func (r *testRoom) join(addr string) (*testSender, uuid.UUID) {
+	ds := &testSender{id: addr}
+	userID := uuid.New()
+	_, _, err := r.service.setNewUser(ds, []byte(r.token(userID)), nil, false)
+	assert.NoError(r.t, err)
+	return ds, userID
+}
+
+func (r *testRoom) send(ds *testSender, payload []byte, reliable bool) {
+	packet := protocol.NewPacket(protocol.TypeData, 1, payload)
+	if reliable {
+		packet.Header.Flags |= protocol.FlagReliable
+		packet.Reliable = 1
+	}
+	messages, err := r.service.relay(ds, packet)
+	assert.NoError(r.t, err)
+	for _, msg := range messages {
+		assert.NotEqual(r.t, protocol.TypeData, msg.Packet.Header.Type, "tick rooms must not relay at once")
+	}
+}
+
+func bundlesFor(t *testing.T, messages []types.Message, userID uuid.UUID) (entries []string, packets []protocol.Packet) {
+	for _, msg := range messages {
+		if msg.Users[0].ID != userID {
+			continue
+		}
+		packets = append(packets, msg.Packet)
+		if msg.Packet.Header.Type != protocol.TypeBundle {
+			entries = append(entries, string(msg.Packet.Payload))
+			continue
+		}
+		parsed, err := protocol.ParseBundle(msg.Packet.Payload)
+		assert.NoError(t, err)
+		for _, entry := range parsed {
+			entries = append(entries, string(entry))
+		}
+	}
+	return entries, packets
+}
+
+func TestTickBundlesRoomTraffic(t *testing.T) {
+	room := newTestRoom(t, 1200)
+	assert.NoError(t, room.service.CreateRoom(room.token(uuid.New()), types.CreateRoomRequest{TickRate: 20}))
+
+	a, aID := room.join("a")
+	b, bID := room.join("b")
+	_, cID := room.join("c")
+
+	room.send(a, []byte("a1"), false)
+	room.send(a, []byte("a2"), true)
+	room.send(b, []byte("b1"), false)
+
+	now := time.Now()
+	messages := room.service.Tick(now)
+
+	entries, packets := bundlesFor(t, messages, aID)
+	assert.Equal(t, []string{"b1"}, entries)
+	assert.False(t, packets[0].IsReliable())
+
+	entries, packets = bundlesFor(t, messages, bID)
+	assert.Equal(t, []string{"a1", "a2"}, entries)
+	assert.Len(t, packets, 1)
+	assert.True(t, packets[0].IsReliable())
+
+	entries, _ = bundlesFor(t, messages, cID)
+	assert.Equal(t, []string{"a1", "a2", "b1"}, entries)
+
+	room.send(a, []byte("a3"), false)
+	assert.Empty(t, room.service.Tick(now.Add(time.Millisecond*10)), "the next tick is not due yet")
+	entries, _ = bundlesFor(t, room.service.Tick(now.Add(time.Millisecond*50)), bID)
+	assert.Equal(t, []string{"a3"}, entries)
+}
+
+func TestTickBundlesFitMTU(t *testing.T) {
+	const mtu = 200
+
+	room := newTestRoom(t, mtu)
+	assert.NoError(t, room.service.CreateRoom(room.token(uuid.New()), types.CreateRoomRequest{TickRate: 60}))
+
+	a, _ := room.join("a")
+	_, bID := room.join("b")
+
+	var sent []string
+	for i := 0; i < 20; i++ {
+		payload := string(rune('a'+i)) + string(make([]byte, 40))
+		sent = append(sent, payload)
+		room.send(a, []byte(payload), false)
+	}
+	large := string(make([]byte, mtu*2))
+	sent = append(sent, large)
+	room.send(a, []byte(large), false)
+
+	entries, packets := bundlesFor(t, room.service.Tick(time.Now()), bID)
+	assert.Equal(t, sent, entries)
+	assert.Greater(t, len(packets), 2)
+	for _, packet := range packets[:len(packets)-1] {
+		assert.Equal(t, protocol.TypeBundle, packet.Header.Type)
+		packet.Header.Flags |= protocol.FlagReliable
+		assert.LessOrEqual(t, packet.Size(), mtu)
+	}
+	assert.Equal(t, protocol.TypeData, packets[len(packets)-1].Header.Type)
+}
+
+func TestExpiredRoomStopsTicking(t *testing.T) {
+	room := newTestRoom(t, 1200)
+	assert.NoError(t, room.service.CreateRoom(room.token(uuid.New()), types.CreateRoomRequest{TickRate: 20}))
+
+	a, _ := room.join("a")
+	room.join("b")
+	room.send(a, []byte("a1"), false)
+
+	roomKey := utils.GenerateRoomKey(room.info)
+	room.service.storage.Remove(roomKey)
+	assert.Empty(t, room.service.Tick(time.Now().Add(time.Second)))
+	_, ticking := room.service.ticking.Load(roomKey)
+	assert.False(t, ticking, "a room that expired stops ticking")
+}
+
+func TestCreateRoomRejectsBadTickRate(t *testing.T) {
+	room := newTestRoom(t, 1200)
+	assert.Error(t, room.service.CreateRoom(room.token(uuid.New()), types.CreateRoomRequest{TickRate: -1}))
+	assert.Error(t, room.service.CreateRoom(room.token(uuid.New()), types.CreateRoomRequest{TickRate: MaxTickRate + 1}))
+}
@@ -0,0 +1,38 @@
+package session
+
+const (
+	replayWindowSize = 64
+)
+
+// replayWindow remembers the last replayWindowSize sequence numbers received
+// on an encrypted session, below the highest one seen.
+type replayWindow struct {
+	highest uint32
+	seen    uint64
+}
+
+func (w *replayWindow) accept(sequence uint32) bool {
+	if sequence > w.highest {
+		shift := sequence - w.highest
+		if shift >= replayWindowSize {
+			w.seen = 1
+		} else {
+			w.seen = w.seen<<shift | 1
+		}
+		w.highest = sequence
+		return true
+	}
+
+	offset := w.highest - sequence
+	if offset >= replayWindowSize {
+		return false
+	}
+
+	bit := uint64(1) << offset
+	if w.seen&bit != 0 {
+		return false
+	}
+	w.seen |= bit
+
+	return true
+}
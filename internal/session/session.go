@@ -0,0 +1,257 @@
+package session
+
+import (
+	"crypto/ecdh"
+	"crypto/rand"
+	"encoding/binary"
+	tokentype "github.com/ascenmmo/token-generator/token_type"
+	"github.com/ascenmmo/udp-server/internal/connection"
+	"github.com/ascenmmo/udp-server/internal/fragment"
+	"github.com/ascenmmo/udp-server/internal/link"
+	"github.com/ascenmmo/udp-server/internal/reliable"
+	"github.com/ascenmmo/udp-server/pkg/errors"
+	"github.com/ascenmmo/udp-server/pkg/protocol"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type Session struct {
+	ID     uint64
+	Key    []byte
+	Info   tokentype.Info
+	Legacy bool
+
+	MTU       int
+	Reliable  *reliable.Channel
+	Fragments *fragment.Reassembler
+	Link      *link.Link
+
+	mu         sync.RWMutex
+	connection connection.DataSender
+	sequence   uint32
+	fragmentID atomic.Uint32
+	lastSeen   atomic.Int64
+
+	cipher       *protocol.Cipher
+	publicKey    []byte
+	replay       replayWindow
+	sendSequence atomic.Uint32
+}
+
+// Touch records that a packet of the session has just been received.
+func (s *Session) Touch() {
+	s.lastSeen.Store(time.Now().UnixNano())
+}
+
+func (s *Session) LastSeen() time.Time {
+	return time.Unix(0, s.lastSeen.Load())
+}
+
+func (s *Session) Connection() connection.DataSender {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.connection
+}
+
+// Observe records a verified packet received from ds. When the packet comes
+// from a new address the session moves there and the previous connection is
+// returned. A packet from another address with an old sequence number is a
+// replay: it never moves the session and fails with ErrPacketReplayed.
+func (s *Session) Observe(ds connection.DataSender, sequence uint32) (old connection.DataSender, migrated bool, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	newer := sequence > s.sequence
+	if newer {
+		s.sequence = sequence
+	}
+
+	if s.connection != nil && s.connection.GetID() == ds.GetID() {
+		return nil, false, nil
+	}
+	if !newer {
+		return nil, false, errors.ErrPacketReplayed
+	}
+
+	old = s.connection
+	s.connection = ds
+	return old, true, nil
+}
+
+// Encrypt switches the session to encrypted mode. The payload and MAC keys are
+// derived from an X25519 exchange with the client's public key, and the
+// server's public key is returned in the handshake reply.
+func (s *Session) Encrypt(peerKey []byte) (err error) {
+	curve := ecdh.X25519()
+	peer, err := curve.NewPublicKey(peerKey)
+	if err != nil {
+		return err
+	}
+	private, err := curve.GenerateKey(rand.Reader)
+	if err != nil {
+		return err
+	}
+	shared, err := private.ECDH(peer)
+	if err != nil {
+		return err
+	}
+
+	encryptionKey, macKey := protocol.DeriveSessionKeys(shared, s.ID)
+	cipher, err := protocol.NewCipher(encryptionKey)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Key = macKey
+	s.cipher = cipher
+	s.publicKey = private.PublicKey().Bytes()
+	s.replay = replayWindow{}
+
+	return nil
+}
+
+func (s *Session) Encrypted() bool {
+	return s.getCipher() != nil
+}
+
+// Open decrypts a packet received from the client. Packets of encrypted
+// sessions must be encrypted, and a sequence number is accepted only once; a
+// replayed packet is still returned decrypted with ErrPacketReplayed, so that
+// a repeated reliable packet can be acknowledged again.
+func (s *Session) Open(packet protocol.Packet) (protocol.Packet, error) {
+	cipher := s.getCipher()
+	if cipher == nil {
+		return packet, nil
+	}
+
+	packet, err := cipher.Open(packet, protocol.ClientToServer)
+	if err != nil {
+		return packet, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.replay.accept(packet.Header.Sequence) {
+		return packet, errors.ErrPacketReplayed
+	}
+
+	return packet, nil
+}
+
+// encode encodes an outbound packet. Packets of encrypted sessions are sealed
+// under the session's own sequence numbers, so that no nonce is used twice.
+func (s *Session) encode(packet protocol.Packet) []byte {
+	cipher := s.getCipher()
+	if cipher == nil {
+		return packet.Encode()
+	}
+
+	packet.Header.Sequence = s.sendSequence.Add(1)
+	return cipher.Seal(packet, protocol.ServerToClient).Encode()
+}
+
+func (s *Session) getCipher() *protocol.Cipher {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.cipher
+}
+
+func (s *Session) Write(buf []byte) error {
+	return s.Connection().Write(buf)
+}
+
+// WritePacket sends a packet to the session's current address, split into
+// fragments that fit the MTU. Reliable packets go through the reliable
+// channel and are retransmitted until acknowledged.
+func (s *Session) WritePacket(packet protocol.Packet) error {
+	return s.WritePacketTo(packet, s.Write)
+}
+
+// WritePacketTo is WritePacket with the datagrams passed to write, for
+// example to batch them. Retransmissions are still written directly.
+func (s *Session) WritePacketTo(packet protocol.Packet, write func([]byte) error) error {
+	mtu := s.mtu()
+
+	var id uint16
+	if packet.Size() > mtu {
+		id = uint16(s.fragmentID.Add(1))
+	}
+
+	fragments, err := packet.Split(mtu, id)
+	if err != nil {
+		return err
+	}
+
+	for _, fragment := range fragments {
+		if fragment.IsReliable() {
+			buf := s.Reliable.Send(fragment, s.encode, s.Write)
+			if buf == nil {
+				continue
+			}
+			err = write(buf)
+		} else {
+			err = write(s.encode(fragment))
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// MaxPayload returns the largest payload packet can carry without being
+// fragmented.
+func (s *Session) MaxPayload(packet protocol.Packet) int {
+	return s.mtu() - packet.Size() + len(packet.Payload)
+}
+
+func (s *Session) mtu() int {
+	if s.Encrypted() {
+		return s.MTU - protocol.TagSize
+	}
+	return s.MTU
+}
+
+// HandshakeReply never carries the key of an encrypted session; the client
+// derives it from the server's public key instead.
+func (s *Session) HandshakeReply() protocol.HandshakeReply {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.cipher != nil {
+		return protocol.HandshakeReply{
+			UserID:    s.Info.UserID,
+			SessionID: s.ID,
+			Key:       make([]byte, protocol.SessionKeySize),
+			PublicKey: s.publicKey,
+		}
+	}
+
+	return protocol.HandshakeReply{
+		UserID:    s.Info.UserID,
+		SessionID: s.ID,
+		Key:       s.Key,
+	}
+}
+
+func NewSession(info tokentype.Info, ds connection.DataSender, legacy bool) (*Session, error) {
+	buf := make([]byte, protocol.SessionIDSize+protocol.SessionKeySize)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, err
+	}
+
+	sess := &Session{
+		ID:         binary.BigEndian.Uint64(buf[:protocol.SessionIDSize]),
+		Key:        buf[protocol.SessionIDSize:],
+		Info:       info,
+		Legacy:     legacy,
+		connection: ds,
+	}
+	sess.Touch()
+
+	return sess, nil
+}
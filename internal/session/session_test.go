@@ -0,0 +1,151 @@
+package session
+
+import (
+	"crypto/ecdh"
+	"crypto/rand"
+	tokentype "github.com/ascenmmo/token-generator/token_type"
+	"github.com/ascenmmo/udp-server/internal/connection"
+	"github.com/ascenmmo/udp-server/internal/reliable"
+	"github.com/ascenmmo/udp-server/pkg/errors"
+	"github.com/ascenmmo/udp-server/pkg/protocol"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+	"time"
+)
+
+// encryptedSession returns an encrypted session and the cipher of its
+// client.
+func encryptedSession(t *testing.T, ds connection.DataSender) (*Session, *protocol.Cipher) {
+	sess, err := NewSession(tokentype.Info{UserID: uuid.New()}, ds, false)
+	assert.NoError(t, err)
+
+	client, err := ecdh.X25519().GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	assert.NoError(t, sess.Encrypt(client.PublicKey().Bytes()))
+
+	reply := sess.HandshakeReply()
+	assert.Equal(t, make([]byte, protocol.SessionKeySize), reply.Key)
+
+	server, err := ecdh.X25519().NewPublicKey(reply.PublicKey)
+	assert.NoError(t, err)
+	shared, err := client.ECDH(server)
+	assert.NoError(t, err)
+	encryptionKey, macKey := protocol.DeriveSessionKeys(shared, reply.SessionID)
+	assert.Equal(t, sess.Key, macKey)
+	cipher, err := protocol.NewCipher(encryptionKey)
+	assert.NoError(t, err)
+
+	return sess, cipher
+}
+
+func TestEncryptedSession(t *testing.T) {
+	sess, cipher := encryptedSession(t, nil)
+
+	sealed := cipher.Seal(protocol.NewPacket(protocol.TypeData, 1, []byte("move")), protocol.ClientToServer)
+	opened, err := sess.Open(sealed)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("move"), opened.Payload)
+
+	_, err = sess.Open(sealed)
+	assert.ErrorIs(t, err, errors.ErrPacketReplayed)
+
+	_, err = sess.Open(protocol.NewPacket(protocol.TypeData, 2, []byte("move")))
+	assert.ErrorIs(t, err, errors.ErrPacketNotEncrypted)
+
+	outbound, err := protocol.Decode(sess.encode(protocol.NewPacket(protocol.TypeData, 1, []byte("state"))))
+	assert.NoError(t, err)
+	received, err := cipher.Open(outbound, protocol.ServerToClient)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("state"), received.Payload)
+}
+
+func TestReplayWindow(t *testing.T) {
+	var w replayWindow
+
+	assert.True(t, w.accept(10))
+	assert.True(t, w.accept(8))
+	assert.False(t, w.accept(8))
+	assert.False(t, w.accept(10))
+	assert.True(t, w.accept(100))
+	assert.False(t, w.accept(36))
+	assert.True(t, w.accept(37))
+}
+
+type recordingSender struct {
+	mu     sync.Mutex
+	writes [][]byte
+}
+
+func (r *recordingSender) GetID() string {
+	return "client"
+}
+
+func (r *recordingSender) Write(buf []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.writes = append(r.writes, buf)
+	return nil
+}
+
+func TestEncryptedRetransmitAfterLostAck(t *testing.T) {
+	ds := &recordingSender{}
+	sess, cipher := encryptedSession(t, ds)
+	sess.MTU = 1200
+	sess.Reliable = reliable.NewChannel(&reliable.Stats{})
+	defer sess.Reliable.Close()
+
+	packet := protocol.NewPacket(protocol.TypeData, 0, []byte("event"))
+	packet.Header.Flags |= protocol.FlagReliable
+	assert.NoError(t, sess.WritePacket(packet))
+	time.Sleep(reliable.RetransmitTimeout + reliable.RetransmitTimeout/2)
+
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+	assert.Len(t, ds.writes, 2, "the ack was lost, so the packet is sent again")
+
+	var window replayWindow
+	for _, buf := range ds.writes {
+		sealed, err := protocol.Decode(buf)
+		assert.NoError(t, err)
+		assert.True(t, window.accept(sealed.Header.Sequence), "every retransmission has a fresh sequence number")
+		opened, err := cipher.Open(sealed, protocol.ServerToClient)
+		assert.NoError(t, err)
+		assert.Equal(t, uint32(1), opened.Reliable)
+		assert.Equal(t, []byte("event"), opened.Payload)
+	}
+}
+
+type addrSender string
+
+func (a addrSender) GetID() string {
+	return string(a)
+}
+
+func (a addrSender) Write([]byte) error {
+	return nil
+}
+
+func TestObserveMigrates(t *testing.T) {
+	sess, err := NewSession(tokentype.Info{UserID: uuid.New()}, addrSender("a"), false)
+	assert.NoError(t, err)
+
+	old, migrated, err := sess.Observe(addrSender("a"), 5)
+	assert.NoError(t, err)
+	assert.False(t, migrated)
+
+	old, migrated, err = sess.Observe(addrSender("b"), 6)
+	assert.NoError(t, err)
+	assert.True(t, migrated)
+	assert.Equal(t, "a", old.GetID())
+	assert.Equal(t, "b", sess.Connection().GetID())
+
+	_, migrated, err = sess.Observe(addrSender("a"), 5)
+	assert.ErrorIs(t, err, errors.ErrPacketReplayed, "an old packet from the old address is a replay")
+	assert.False(t, migrated)
+	assert.Equal(t, "b", sess.Connection().GetID())
+
+	_, _, err = sess.Observe(addrSender("b"), 4)
+	assert.NoError(t, err, "packets may arrive out of order on the current address")
+}
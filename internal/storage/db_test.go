@@ -9,6 +9,7 @@ import (
 	"strconv"
 	"sync"
 	"testing"
+	"time"
 )
 
 func TestDB(t *testing.T) {
@@ -121,3 +122,27 @@ func setNewRoomData(db *MemoryDb) {
 
 	db.SetData("1", &room)
 }
+
+func TestRangeDataAndTTL(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	db := NewMemoryDb(ctx, 5)
+
+	db.SetDataWithTTL("room:a", 1, time.Hour)
+	db.SetData("room:b", 2)
+	db.SetData("session:c", 3)
+
+	found := map[string]any{}
+	db.RangeData("room:", func(key string, value any) bool {
+		found[key] = value
+		return true
+	})
+	assert.Equal(t, map[string]any{"room:a": 1, "room:b": 2}, found)
+
+	db.GetData("room:a")
+	expiresAt, ok := db.ExpiresAt("room:a")
+	assert.True(t, ok)
+	assert.WithinDuration(t, time.Now().Add(time.Hour+5*time.Second), expiresAt, time.Second, "reading a value keeps its TTL")
+	_, ok = db.ExpiresAt("room:c")
+	assert.False(t, ok)
+}
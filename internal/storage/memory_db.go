@@ -2,6 +2,7 @@ package memoryDB
 
 import (
 	"context"
+	"strings"
 	"sync"
 	"time"
 )
@@ -11,6 +12,8 @@ type IMemoryDB interface {
 	SetData(key string, value any)
 	SetDataWithTTL(key string, value any, ttl time.Duration)
 	Remove(key string)
+	RangeData(prefix string, f func(key string, value any) bool)
+	ExpiresAt(key string) (expiresAt time.Time, ok bool)
 
 	AddConnection(id string)
 	RemoveConnection(id string)
@@ -46,7 +49,9 @@ func (db *MemoryDb) GetData(key string) (any, bool) {
 	}
 
 	row := value.(*rowType)
-	row.usedAt = time.Now()
+	if now := time.Now(); row.usedAt.Before(now) {
+		row.usedAt = now
+	}
 	return row.value, true
 }
 
@@ -60,6 +65,28 @@ func (db *MemoryDb) SetDataWithTTL(key string, value any, ttl time.Duration) {
 	db.userData.count++
 }
 
+// RangeData calls f for every value whose key starts with prefix until f
+// returns false. Unlike GetData, it does not keep the values alive.
+func (db *MemoryDb) RangeData(prefix string, f func(key string, value any) bool) {
+	db.userData.storage.Range(func(key, value any) bool {
+		k, ok := key.(string)
+		if !ok || !strings.HasPrefix(k, prefix) {
+			return true
+		}
+		return f(k, value.(*rowType).value)
+	})
+}
+
+// ExpiresAt returns when the value of key is removed unless it is used
+// before.
+func (db *MemoryDb) ExpiresAt(key string) (expiresAt time.Time, ok bool) {
+	value, ok := db.userData.storage.Load(key)
+	if !ok {
+		return expiresAt, false
+	}
+	return value.(*rowType).usedAt.Add(time.Second * db.dataTTL), true
+}
+
 func (db *MemoryDb) AddConnection(id string) {
 	db.connections.storage.Store(id, &rowType{value: id, usedAt: time.Now()})
 	db.connections.count++
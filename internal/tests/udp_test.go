@@ -36,6 +36,7 @@ var maxMsgs int
 func TestConnection(t *testing.T) {
 	//logger := zerolog.New(os.Stdout).With().Timestamp().Logger()
 	logger := zerolog.Logger{}
+	env.LegacyProtocol = true
 
 	go start.StartUDP(
 		ctx,
@@ -3,6 +3,7 @@ package utils
 import (
 	"fmt"
 	tokentype "github.com/ascenmmo/token-generator/token_type"
+	"github.com/google/uuid"
 )
 
 const (
@@ -10,9 +11,39 @@ const (
 )
 
 func GenerateRoomKey(clientInfo tokentype.Info) string {
-	return fmt.Sprintf("game:%s-room:%s", clientInfo.GameID, clientInfo.RoomID)
+	return GenerateGameRoomsPrefix(clientInfo.GameID) + clientInfo.RoomID.String()
+}
+
+// GenerateRoomGuardKey returns the key that keeps a room from being created
+// by a handshake.
+func GenerateRoomGuardKey(clientInfo tokentype.Info) string {
+	return "guard:" + GenerateRoomKey(clientInfo)
+}
+
+// GenerateBanKey returns the key of the ban of the token's user from its room.
+func GenerateBanKey(clientInfo tokentype.Info) string {
+	return GenerateRoomBansPrefix(clientInfo) + clientInfo.UserID.String()
+}
+
+// GenerateRoomBansPrefix returns the prefix of the keys of the bans of a room.
+func GenerateRoomBansPrefix(clientInfo tokentype.Info) string {
+	return "ban:" + GenerateRoomKey(clientInfo) + "-user:"
+}
+
+// GenerateGameBansPrefix returns the prefix of the keys of the bans of a game.
+func GenerateGameBansPrefix(gameID uuid.UUID) string {
+	return "ban:" + GenerateGameRoomsPrefix(gameID)
+}
+
+// GenerateGameRoomsPrefix returns the prefix of the keys of the rooms of a game.
+func GenerateGameRoomsPrefix(gameID uuid.UUID) string {
+	return fmt.Sprintf("game:%s-room:", gameID)
 }
 
 func GenerateNotifyServerKey() string {
 	return serverKey
 }
+
+func GenerateSessionKey(sessionID uint64) string {
+	return fmt.Sprintf("session:%d", sessionID)
+}
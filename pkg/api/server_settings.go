@@ -33,4 +33,52 @@ type ServerSettings interface {
 	// @tg http-headers=token|Token
 	// @tg summary=`GetDeletedRooms`
 	GetDeletedRooms(ctx context.Context, token string, ids []types.GetDeletedRooms) (deletedIds []types.GetDeletedRooms, err error)
+	// @tg http-headers=token|Token
+	// @tg summary=`GetRoomStats`
+	GetRoomStats(ctx context.Context, token string) (stats types.RoomStats, err error)
+	// @tg http-headers=token|Token
+	// @tg summary=`GetLinkQuality`
+	GetLinkQuality(ctx context.Context, token string) (quality []types.LinkQuality, err error)
+	// @tg http-headers=token|Token
+	// @tg summary=`SetGameSettings`
+	SetGameSettings(ctx context.Context, token string, settings types.GameSettings) (err error)
+	// @tg http-headers=token|Token
+	// @tg summary=`GetGameSettings`
+	GetGameSettings(ctx context.Context, token string) (settings types.GameSettings, err error)
+	// @tg http-headers=token|Token
+	// @tg summary=`JoinGroup`
+	JoinGroup(ctx context.Context, token string, name string) (err error)
+	// @tg http-headers=token|Token
+	// @tg summary=`LeaveGroup`
+	LeaveGroup(ctx context.Context, token string, name string) (err error)
+	// @tg http-headers=token|Token
+	// @tg summary=`GetGroups`
+	GetGroups(ctx context.Context, token string) (groups []types.Group, err error)
+	// @tg http-headers=token|Token
+	// @tg summary=`ListRooms`
+	ListRooms(ctx context.Context, token string, listRooms types.ListRoomsRequest) (rooms types.RoomList, err error)
+	// @tg http-headers=token|Token
+	// @tg summary=`GetRoom`
+	GetRoom(ctx context.Context, token string, room types.RoomKey) (roomInfo types.RoomInfo, err error)
+	// @tg http-headers=token|Token
+	// @tg summary=`CloseRoom`
+	CloseRoom(ctx context.Context, token string, closeRoom types.CloseRoomRequest) (err error)
+	// @tg http-headers=token|Token
+	// @tg summary=`UpdateRoom`
+	UpdateRoom(ctx context.Context, token string, updateRoom types.UpdateRoomRequest) (err error)
+	// @tg http-headers=token|Token
+	// @tg summary=`KickUser`
+	KickUser(ctx context.Context, token string, kickUser types.KickUserRequest) (err error)
+	// @tg http-headers=token|Token
+	// @tg summary=`BanUser`
+	BanUser(ctx context.Context, token string, banUser types.BanUserRequest) (err error)
+	// @tg http-headers=token|Token
+	// @tg summary=`GetRoomBans`
+	GetRoomBans(ctx context.Context, token string, room types.RoomKey) (bans []types.Ban, err error)
+	// @tg http-headers=token|Token
+	// @tg summary=`GetGameBans`
+	GetGameBans(ctx context.Context, token string) (bans []types.Ban, err error)
+	// @tg http-headers=token|Token
+	// @tg summary=`SetRoomMetadata`
+	SetRoomMetadata(ctx context.Context, token string, setRoomMetadata types.SetRoomMetadataRequest) (version uint64, err error)
 }
@@ -0,0 +1,8 @@
+package types
+
+import "github.com/ascenmmo/udp-server/pkg/protocol"
+
+type Message struct {
+	Users  []User
+	Packet protocol.Packet
+}
@@ -0,0 +1,147 @@
+package types
+
+import (
+	"github.com/ascenmmo/udp-server/pkg/errors"
+	"github.com/google/uuid"
+	"maps"
+	"strings"
+)
+
+// Limits of the metadata of a room. The size of the metadata is the sum of
+// the lengths of its keys and values.
+const (
+	MaxMetadataKey   = 255
+	MaxMetadataValue = 1024
+	MaxMetadataSize  = 16 << 10
+)
+
+// UserMetadataKey returns the key name of user. Only the user itself writes
+// its keys, and they are removed when it leaves the room. Every other key is
+// written by the owner of the room.
+func UserMetadataKey(user uuid.UUID, name string) string {
+	return user.String() + "/" + name
+}
+
+// metadataUser returns the user a key belongs to.
+func metadataUser(key string) (user uuid.UUID, ok bool) {
+	prefix, _, found := strings.Cut(key, "/")
+	if !found {
+		return user, false
+	}
+	user, err := uuid.Parse(prefix)
+	return user, err == nil && len(prefix) == len(uuid.Nil.String())
+}
+
+// CheckMetadata checks the limits of a set of metadata values.
+func CheckMetadata(values map[string]string) error {
+	size := 0
+	for key, value := range values {
+		if len(key) == 0 || len(key) > MaxMetadataKey || len(value) > MaxMetadataValue {
+			return errors.ErrMetadataTooLarge
+		}
+		size += len(key) + len(value)
+	}
+	if size > MaxMetadataSize {
+		return errors.ErrMetadataTooLarge
+	}
+	return nil
+}
+
+// Metadata returns the metadata of the room and its version.
+func (r *Room) Metadata() (version uint64, values map[string]string) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.metadataVersion, maps.Clone(r.metadata)
+}
+
+// writable reports whether by may write key. uuid.Nil stands for the game
+// backend, which writes every key.
+func (r *Room) writable(by uuid.UUID, key string) bool {
+	if by == uuid.Nil {
+		return true
+	}
+	user, ok := metadataUser(key)
+	return ok && user == by || !ok && by == r.owner
+}
+
+// ReplaceMetadata replaces every key of the metadata that by may write with
+// values, leaving the keys of others alone, and returns like UpdateMetadata.
+func (r *Room) ReplaceMetadata(by uuid.UUID, values map[string]string) (version uint64, applied map[string]string, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	changes := maps.Clone(values)
+	if changes == nil {
+		changes = make(map[string]string, len(r.metadata))
+	}
+	for key := range r.metadata {
+		if _, ok := changes[key]; !ok && r.writable(by, key) {
+			changes[key] = ""
+		}
+	}
+	return r.updateMetadata(by, changes)
+}
+
+// UpdateMetadata sets the given keys of the metadata; an empty value removes
+// a key. The changes are applied together or not at all. Users may only
+// change their own keys, the owner the other ones, and uuid.Nil, the game
+// backend, every key. It returns the new version and the changes that took
+// effect, which are empty when nothing changed.
+func (r *Room) UpdateMetadata(by uuid.UUID, changes map[string]string) (version uint64, applied map[string]string, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.updateMetadata(by, changes)
+}
+
+func (r *Room) updateMetadata(by uuid.UUID, changes map[string]string) (version uint64, applied map[string]string, err error) {
+	applied = make(map[string]string, len(changes))
+	for key, value := range changes {
+		if !r.writable(by, key) {
+			return r.metadataVersion, nil, errors.ErrMetadataForbidden
+		}
+		if current, ok := r.metadata[key]; ok && current == value || !ok && value == "" {
+			continue
+		}
+		applied[key] = value
+	}
+
+	next := maps.Clone(r.metadata)
+	if next == nil {
+		next = make(map[string]string, len(applied))
+	}
+	for key, value := range applied {
+		if value == "" {
+			delete(next, key)
+			continue
+		}
+		next[key] = value
+	}
+	if err = CheckMetadata(next); err != nil {
+		return r.metadataVersion, nil, err
+	}
+
+	if len(applied) > 0 {
+		r.metadata = next
+		r.metadataVersion++
+	}
+	return r.metadataVersion, applied, nil
+}
+
+// RemoveUserMetadata removes the keys of user and returns the new version
+// with the removals, which are empty when the user had no keys.
+func (r *Room) RemoveUserMetadata(user uuid.UUID) (version uint64, removed map[string]string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	removed = make(map[string]string)
+	for key := range r.metadata {
+		if owner, ok := metadataUser(key); ok && owner == user {
+			removed[key] = ""
+			delete(r.metadata, key)
+		}
+	}
+	if len(removed) > 0 {
+		r.metadataVersion++
+	}
+	return r.metadataVersion, removed
+}
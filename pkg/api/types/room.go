@@ -2,10 +2,24 @@ package types
 
 import (
 	"github.com/ascenmmo/udp-server/internal/connection"
+	"github.com/ascenmmo/udp-server/internal/interest"
+	"github.com/ascenmmo/udp-server/internal/lockstep"
+	"github.com/ascenmmo/udp-server/internal/reliable"
+	"github.com/ascenmmo/udp-server/internal/rollback"
+	"github.com/ascenmmo/udp-server/internal/session"
+	"github.com/ascenmmo/udp-server/pkg/errors"
+	"github.com/ascenmmo/udp-server/pkg/protocol"
 	"github.com/google/uuid"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
+// MaxRoomGroups is the number of groups a room can have at once.
+const MaxRoomGroups = 64
+
 type Room struct {
 	GameID uuid.UUID
 	RoomID uuid.UUID
@@ -14,32 +28,411 @@ type Room struct {
 
 	Users []*User
 
+	CreatedAt time.Time
 	UpdatedAt time.Time
+
+	// TickRate is the number of ticks per second of a room in tick mode, or
+	// zero when packets are relayed as they arrive.
+	TickRate int
+	// NoAutoCreate keeps handshakes from creating the room again once it
+	// is closed or expired.
+	NoAutoCreate bool
+
+	// Lockstep holds the inputs of a room in lockstep mode.
+	Lockstep *lockstep.Buffer
+	// Rollback holds the inputs of a room in rollback mode.
+	Rollback *rollback.Buffer
+	// Interest holds the positions of the members of a room with
+	// area-of-interest filtering.
+	Interest *interest.Grid
+
+	Reliable reliable.Stats
+	Traffic  Traffic
+
+	mu              sync.RWMutex
+	groups          map[string][]uuid.UUID
+	maxUsers        int
+	allowed         map[uuid.UUID]struct{}
+	metadata        map[string]string
+	metadataVersion uint64
+	locked          bool
+	owner           uuid.UUID
+	// joins numbers the members in the order they joined, for host
+	// migration.
+	joins   map[uuid.UUID]uint64
+	joinSeq uint64
+
+	tickMu   sync.Mutex
+	tick     uint32
+	nextTick time.Time
+	held     []HeldMessage
+}
+
+// Traffic counts the data messages relayed by a room.
+type Traffic struct {
+	MessagesIn  atomic.Uint64
+	BytesIn     atomic.Uint64
+	MessagesOut atomic.Uint64
+	BytesOut    atomic.Uint64
+}
+
+func (t *Traffic) In(size int) {
+	t.MessagesIn.Add(1)
+	t.BytesIn.Add(uint64(size))
+}
+
+func (t *Traffic) Out(recipients, size int) {
+	t.MessagesOut.Add(uint64(recipients))
+	t.BytesOut.Add(uint64(recipients * size))
+}
+
+// HeldMessage is a message of a tick-mode room waiting for the next tick.
+type HeldMessage struct {
+	From uuid.UUID
+	// To lists the recipients, or is nil when the message goes to every
+	// member but the sender.
+	To       []uuid.UUID
+	Payload  []byte
+	Reliable bool
+}
+
+// IsFor reports whether user receives the message.
+func (m HeldMessage) IsFor(user uuid.UUID) bool {
+	if m.To == nil {
+		return m.From != user
+	}
+	return slices.Contains(m.To, user)
 }
 
 type User struct {
 	ID uuid.UUID
 
 	Connection connection.DataSender
+	Legacy     bool
+	Session    *session.Session
+}
+
+func (u *User) Write(packet protocol.Packet) error {
+	if !u.Legacy && u.Session != nil {
+		return u.Session.WritePacket(packet)
+	}
+	return u.Connection.Write(packet.Marshal(u.Legacy))
+}
+
+// WriteBatch is Write with the datagrams queued on batch.
+func (u *User) WriteBatch(packet protocol.Packet, batch *connection.Batch) error {
+	if !u.Legacy && u.Session != nil {
+		return u.Session.WritePacketTo(packet, batch.Writer(u.Session.Connection()))
+	}
+	return batch.Add(u.Connection, packet.Marshal(u.Legacy))
 }
 
 func (r *Room) SetUser(user *User) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 	r.Users = r.setUser(r.Users, user)
 }
 
+// Join sets the user of the room and reports whether it was not a member
+// yet. A new member is refused when the room is locked, is full or has an
+// allowlist without the user. The first member of a room without an owner
+// becomes its owner.
+func (r *Room) Join(user *User) (joined bool, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	joined = !slices.ContainsFunc(r.Users, func(u *User) bool { return u.ID == user.ID })
+	if _, ok := r.allowed[user.ID]; joined && r.allowed != nil && !ok {
+		return false, errors.ErrNotInvited
+	}
+	if joined && r.locked {
+		return false, errors.ErrRoomLocked
+	}
+	if joined && r.maxUsers > 0 && len(r.Users) >= r.maxUsers {
+		return false, errors.ErrRoomFull
+	}
+	r.Users = r.setUser(r.Users, user)
+	if joined {
+		r.UpdatedAt = time.Now()
+		if r.joins == nil {
+			r.joins = make(map[uuid.UUID]uint64)
+		}
+		r.joinSeq++
+		r.joins[user.ID] = r.joinSeq
+		if r.owner == uuid.Nil {
+			r.owner = user.ID
+		}
+	}
+
+	return joined, nil
+}
+
+// MaxUsers returns the capacity of the room, or zero when it has none.
+func (r *Room) MaxUsers() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.maxUsers
+}
+
+func (r *Room) SetMaxUsers(maxUsers int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.maxUsers = maxUsers
+}
+
+// AllowedUsers returns the allowlist of the room, or nil when anyone may join.
+func (r *Room) AllowedUsers() (users []uuid.UUID) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for user := range r.allowed {
+		users = append(users, user)
+	}
+	slices.SortFunc(users, func(a, b uuid.UUID) int { return strings.Compare(a.String(), b.String()) })
+	return users
+}
+
+// SetAllowedUsers limits who may join the room. Members that are not listed
+// stay in the room; an empty list lets anyone join.
+func (r *Room) SetAllowedUsers(users []uuid.UUID) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.allowed = nil
+	if len(users) == 0 {
+		return
+	}
+	r.allowed = make(map[uuid.UUID]struct{}, len(users))
+	for _, user := range users {
+		r.allowed[user] = struct{}{}
+	}
+}
+
 func (r *Room) GetUser() (users []*User) {
-	users = r.Users
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	users = append(users, r.Users...)
 	return
 }
 
-func (r *Room) RemoveUser(user uuid.UUID) {
+func (r *Room) GetUserByID(userID uuid.UUID) (user *User, ok bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, user := range r.Users {
+		if user.ID == userID {
+			return user, true
+		}
+	}
+	return nil, false
+}
+
+// RemoveUser removes a member from the room, its groups and the
+// area-of-interest grid. When the member was the owner,
+// the next owner is picked among the remaining members by betterOwner and
+// returned with changed set.
+func (r *Room) RemoveUser(user uuid.UUID) (owner uuid.UUID, changed bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 	r.removeFromArray(user)
+	delete(r.joins, user)
+	r.UpdatedAt = time.Now()
+	for name := range r.groups {
+		r.leaveGroup(name, user)
+	}
+	if r.Interest != nil {
+		r.Interest.Remove(user)
+	}
+
+	if r.owner != user {
+		return r.owner, false
+	}
+	r.owner = uuid.Nil
+	var next *User
+	for _, u := range r.Users {
+		if next == nil || r.betterOwner(u, next) {
+			next = u
+		}
+	}
+	if next != nil {
+		r.owner = next.ID
+	}
+	return r.owner, true
+}
+
+// betterOwner reports whether a should become the owner before b: members
+// that are not legacy clients come first, then members in join order, then
+// members that were set without joining, by ID.
+func (r *Room) betterOwner(a, b *User) bool {
+	if a.Legacy != b.Legacy {
+		return !a.Legacy
+	}
+	ja, jb := r.joins[a.ID], r.joins[b.ID]
+	if ja != jb {
+		return ja != 0 && (jb == 0 || ja < jb)
+	}
+	return strings.Compare(a.ID.String(), b.ID.String()) < 0
+}
+
+// Owner returns the owner of the room, or uuid.Nil when it has none.
+func (r *Room) Owner() uuid.UUID {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.owner
+}
+
+func (r *Room) SetOwner(owner uuid.UUID) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.owner = owner
+}
+
+// Locked reports whether the room refuses new members.
+func (r *Room) Locked() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.locked
+}
+
+func (r *Room) SetLocked(locked bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.locked = locked
+}
+
+// JoinGroup adds a member of the room to a group, creating the group when it
+// does not exist. It reports whether the user was not in the group yet.
+func (r *Room) JoinGroup(name string, userID uuid.UUID) (joined bool, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !slices.ContainsFunc(r.Users, func(user *User) bool { return user.ID == userID }) {
+		return false, errors.ErrUserNotFound
+	}
+
+	members, ok := r.groups[name]
+	if slices.Contains(members, userID) {
+		return false, nil
+	}
+	if !ok && len(r.groups) >= MaxRoomGroups {
+		return false, errors.ErrTooManyGroups
+	}
+	if r.groups == nil {
+		r.groups = make(map[string][]uuid.UUID)
+	}
+	r.groups[name] = append(members, userID)
+
+	return true, nil
+}
+
+// LeaveGroup removes the user from a group and reports whether it was in it.
+// A group is removed with its last member.
+func (r *Room) LeaveGroup(name string, userID uuid.UUID) (left bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.leaveGroup(name, userID)
+}
+
+func (r *Room) leaveGroup(name string, userID uuid.UUID) bool {
+	members := r.groups[name]
+	i := slices.Index(members, userID)
+	if i < 0 {
+		return false
+	}
+	members = slices.Delete(members, i, i+1)
+	if len(members) == 0 {
+		delete(r.groups, name)
+	} else {
+		r.groups[name] = members
+	}
+	return true
+}
+
+// GroupMembers returns the members of a group in the order they joined.
+func (r *Room) GroupMembers(name string) (members []uuid.UUID, ok bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	members, ok = r.groups[name]
+	return slices.Clone(members), ok
+}
+
+// Groups returns the groups of the room sorted by name.
+func (r *Room) Groups() (groups []Group) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for name, members := range r.groups {
+		groups = append(groups, Group{Name: name, Users: slices.Clone(members)})
+	}
+	slices.SortFunc(groups, func(a, b Group) int { return strings.Compare(a.Name, b.Name) })
+	return groups
+}
+
+// Hold keeps a message until the next tick. The payload is copied.
+func (r *Room) Hold(from uuid.UUID, to []uuid.UUID, payload []byte, reliable bool) {
+	r.tickMu.Lock()
+	defer r.tickMu.Unlock()
+	r.held = append(r.held, HeldMessage{
+		From:     from,
+		To:       to,
+		Payload:  append([]byte(nil), payload...),
+		Reliable: reliable,
+	})
+}
+
+// Tick returns the number of the tick and the messages held since the last
+// one when a tick is due at now. A room that falls behind skips the ticks it
+// missed.
+func (r *Room) Tick(now time.Time) (tick uint32, held []HeldMessage, due bool) {
+	if r.TickRate <= 0 {
+		return 0, nil, false
+	}
+
+	r.tickMu.Lock()
+	defer r.tickMu.Unlock()
+
+	if now.Before(r.nextTick) {
+		return 0, nil, false
+	}
+
+	interval := time.Second / time.Duration(r.TickRate)
+	r.nextTick = r.nextTick.Add(interval)
+	if r.nextTick.Before(now) {
+		r.nextTick = now.Add(interval)
+	}
+	r.tick++
+
+	held, r.held = r.held, nil
+	return r.tick, held, true
+}
+
+func (r *Room) ReliableStats() ReliableStats {
+	return ReliableStats{
+		Sent:        r.Reliable.Sent.Load(),
+		Retransmits: r.Reliable.Retransmits.Load(),
+		Acks:        r.Reliable.Acks.Load(),
+		Duplicates:  r.Reliable.Duplicates.Load(),
+		Dropped:     r.Reliable.Dropped.Load(),
+	}
 }
 
 func (r *Room) SetUpdatedAt() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 	r.UpdatedAt = time.Now()
 }
 
+func (r *Room) GetUpdatedAt() time.Time {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.UpdatedAt
+}
+
+func (r *Room) TrafficStats() TrafficStats {
+	return TrafficStats{
+		MessagesIn:  r.Traffic.MessagesIn.Load(),
+		BytesIn:     r.Traffic.BytesIn.Load(),
+		MessagesOut: r.Traffic.MessagesOut.Load(),
+		BytesOut:    r.Traffic.BytesOut.Load(),
+	}
+}
+
 func (r *Room) setUser(users []*User, user *User) (allUsers []*User) {
 	uniqueUsers := make(map[uuid.UUID]*User)
 	for _, user := range users {
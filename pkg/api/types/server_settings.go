@@ -5,11 +5,177 @@ import (
 	"time"
 )
 
+type RoomMode string
+
+const (
+	// RoomModeRelay relays packets to the room as they arrive, or on every
+	// tick when TickRate is set.
+	RoomModeRelay RoomMode = ""
+	// RoomModeLockstep collects the members' inputs per frame and sends each
+	// frame to the room once every member has reported or InputTimeout
+	// passed.
+	RoomModeLockstep RoomMode = "lockstep"
+	// RoomModeRollback keeps the last InputHistory frames of inputs of every
+	// player and sends each member the inputs after the frame it confirmed.
+	RoomModeRollback RoomMode = "rollback"
+)
+
 type CreateRoomRequest struct {
-	RoomTTl time.Duration `json:"roomTTl"`
+	RoomTTl      time.Duration `json:"roomTTl"`
+	TickRate     int           `json:"tickRate"`
+	Mode         RoomMode      `json:"mode"`
+	InputDelay   uint32        `json:"inputDelay"`
+	InputTimeout time.Duration `json:"inputTimeout"`
+	InputHistory int           `json:"inputHistory"`
+	Interest     Interest      `json:"interest"`
+	MaxUsers     int           `json:"maxUsers"`
+	NoAutoCreate bool          `json:"noAutoCreate"`
+	AllowedUsers []uuid.UUID   `json:"allowedUsers"`
+	Owner        uuid.UUID     `json:"owner"`
+}
+
+// Interest enables area-of-interest filtering when CellSize is set: the
+// messages a member sends to the room reach only the members within Radius of
+// its position or, when Radius is zero, within Cells cells of its cell. A
+// member leaves the area only once it is Hysteresis beyond it.
+type Interest struct {
+	CellSize   float64 `json:"cellSize"`
+	Radius     float64 `json:"radius"`
+	Cells      int     `json:"cells"`
+	Hysteresis float64 `json:"hysteresis"`
 }
 
 type GetDeletedRooms struct {
 	GameID uuid.UUID `json:"gameID"`
 	RoomID uuid.UUID `json:"roomID"`
 }
+
+type RoomKey struct {
+	GameID uuid.UUID `json:"gameID"`
+	RoomID uuid.UUID `json:"roomID"`
+}
+
+type ListRoomsRequest struct {
+	GameID uuid.UUID `json:"gameID"`
+	Offset int       `json:"offset"`
+	Limit  int       `json:"limit"`
+}
+
+type RoomList struct {
+	Rooms []RoomInfo `json:"rooms"`
+	Total int        `json:"total"`
+}
+
+type RoomInfo struct {
+	GameID          uuid.UUID         `json:"gameID"`
+	RoomID          uuid.UUID         `json:"roomID"`
+	Users           []uuid.UUID       `json:"users"`
+	CreatedAt       time.Time         `json:"createdAt"`
+	UpdatedAt       time.Time         `json:"updatedAt"`
+	TTL             time.Duration     `json:"ttl"`
+	Owner           uuid.UUID         `json:"owner"`
+	Locked          bool              `json:"locked"`
+	MaxUsers        int               `json:"maxUsers"`
+	AllowedUsers    []uuid.UUID       `json:"allowedUsers"`
+	Metadata        map[string]string `json:"metadata"`
+	MetadataVersion uint64            `json:"metadataVersion"`
+	Traffic         TrafficStats      `json:"traffic"`
+	Reliable        ReliableStats     `json:"reliable"`
+}
+
+type CloseRoomRequest struct {
+	GameID uuid.UUID `json:"gameID"`
+	RoomID uuid.UUID `json:"roomID"`
+	Reason string    `json:"reason"`
+}
+
+// UpdateRoomRequest changes the fields that are set. An empty Metadata map
+// clears the metadata, and a MaxUsers of zero removes the limit.
+type UpdateRoomRequest struct {
+	GameID   uuid.UUID         `json:"gameID"`
+	RoomID   uuid.UUID         `json:"roomID"`
+	RoomTTl  *time.Duration    `json:"roomTTl"`
+	MaxUsers *int              `json:"maxUsers"`
+	Metadata map[string]string `json:"metadata"`
+}
+
+// KickUserRequest removes a user from a room. A non-zero Duration also
+// refuses the user's handshakes for that long.
+type KickUserRequest struct {
+	GameID   uuid.UUID     `json:"gameID"`
+	RoomID   uuid.UUID     `json:"roomID"`
+	UserID   uuid.UUID     `json:"userID"`
+	Duration time.Duration `json:"duration"`
+	Reason   string        `json:"reason"`
+}
+
+// BanUserRequest removes a user from a room and refuses its handshakes for
+// Duration, or for good when Duration is zero.
+type BanUserRequest struct {
+	GameID   uuid.UUID     `json:"gameID"`
+	RoomID   uuid.UUID     `json:"roomID"`
+	UserID   uuid.UUID     `json:"userID"`
+	Duration time.Duration `json:"duration"`
+	Reason   string        `json:"reason"`
+}
+
+type Ban struct {
+	GameID    uuid.UUID `json:"gameID"`
+	RoomID    uuid.UUID `json:"roomID"`
+	UserID    uuid.UUID `json:"userID"`
+	Reason    string    `json:"reason"`
+	CreatedAt time.Time `json:"createdAt"`
+	// ExpiresAt is zero for a permanent ban.
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+func (b Ban) Expired(now time.Time) bool {
+	return !b.ExpiresAt.IsZero() && !now.Before(b.ExpiresAt)
+}
+
+// SetRoomMetadataRequest changes the given keys of the metadata of a room.
+// An empty value removes a key.
+type SetRoomMetadataRequest struct {
+	GameID uuid.UUID         `json:"gameID"`
+	RoomID uuid.UUID         `json:"roomID"`
+	Values map[string]string `json:"values"`
+}
+
+type TrafficStats struct {
+	MessagesIn  uint64 `json:"messagesIn"`
+	BytesIn     uint64 `json:"bytesIn"`
+	MessagesOut uint64 `json:"messagesOut"`
+	BytesOut    uint64 `json:"bytesOut"`
+}
+
+type RoomStats struct {
+	Users    int           `json:"users"`
+	Reliable ReliableStats `json:"reliable"`
+}
+
+type ReliableStats struct {
+	Sent        uint64 `json:"sent"`
+	Retransmits uint64 `json:"retransmits"`
+	Acks        uint64 `json:"acks"`
+	Duplicates  uint64 `json:"duplicates"`
+	Dropped     uint64 `json:"dropped"`
+}
+
+type LinkQuality struct {
+	UserID   uuid.UUID     `json:"userID"`
+	RTT      time.Duration `json:"rtt"`
+	Jitter   time.Duration `json:"jitter"`
+	Loss     float64       `json:"loss"`
+	Pings    uint64        `json:"pings"`
+	Pongs    uint64        `json:"pongs"`
+	LastSeen time.Time     `json:"lastSeen"`
+}
+
+type Group struct {
+	Name  string      `json:"name"`
+	Users []uuid.UUID `json:"users"`
+}
+
+type GameSettings struct {
+	Encryption bool `json:"encryption"`
+}
@@ -2,28 +2,66 @@ package types
 
 import (
 	"github.com/ascenmmo/udp-server/env"
+	"net"
 	"runtime"
+	"slices"
 )
 
 type Settings struct {
-	ServerType          string `json:"serverType"`
-	ServerPort          string `json:"serverPort"`
-	ConnectionPort      string `json:"connectionPort"`
-	ServerAddress       string `json:"serverAddress"`
-	MaxConnections      int    `json:"maxConnections"`
-	MaxRequestPerSecond int    `json:"maxRequestPerSecond"`
+	ServerType          string   `json:"serverType"`
+	ServerPort          string   `json:"serverPort"`
+	ConnectionPort      string   `json:"connectionPort"`
+	ServerAddress       string   `json:"serverAddress"`
+	UDPEndpoints        []string `json:"udpEndpoints"`
+	MaxConnections      int      `json:"maxConnections"`
+	MaxRequestPerSecond int      `json:"maxRequestPerSecond"`
 }
 
-func NewSettings() (settings Settings) {
+// NewSettings describes a server serving its API on tcpPort and accepting
+// connections on udpPort.
+func NewSettings(tcpPort, udpPort string) (settings Settings) {
 	settings.ServerType = "upd"
-	settings.ServerPort = env.TCPPort
-	settings.ConnectionPort = env.UDPPort
+	settings.ServerPort = tcpPort
+	settings.ConnectionPort = udpPort
 	settings.ServerAddress = env.ServerAddress
+	settings.UDPEndpoints = udpEndpoints(udpPort)
 	settings.MaxConnections = CountConnectionsMAX()
 	settings.MaxRequestPerSecond = env.MaxRequestPerSecond
 	return settings
 }
 
+// udpEndpoints returns the host:port endpoints clients may connect to. Unless
+// they are configured, they are the listen addresses with wildcard hosts
+// replaced by ServerAddress. Without listen addresses the server listens on
+// udpPort.
+func udpEndpoints(udpPort string) (endpoints []string) {
+	if len(env.UDPEndpoints) > 0 {
+		return env.UDPEndpoints
+	}
+
+	listen := env.UDPListenAddresses
+	if len(listen) == 0 {
+		listen = []string{":" + udpPort}
+	}
+
+	for _, addr := range listen {
+		host, port, err := net.SplitHostPort(addr)
+		if err != nil {
+			continue
+		}
+		if ip := net.ParseIP(host); host == "" || ip != nil && ip.IsUnspecified() {
+			host = env.ServerAddress
+		}
+
+		endpoint := net.JoinHostPort(host, port)
+		if !slices.Contains(endpoints, endpoint) {
+			endpoints = append(endpoints, endpoint)
+		}
+	}
+
+	return endpoints
+}
+
 func CountConnectionsMAX() int {
 	numCPUs := runtime.NumCPU()
 
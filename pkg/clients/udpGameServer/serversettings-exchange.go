@@ -44,3 +44,135 @@ type requestServerSettingsGetDeletedRooms struct {
 type responseServerSettingsGetDeletedRooms struct {
 	DeletedIds []types.GetDeletedRooms `json:"deletedIds"`
 }
+
+type requestServerSettingsGetRoomStats struct {
+	Token string `json:"token"`
+}
+
+type responseServerSettingsGetRoomStats struct {
+	Stats types.RoomStats `json:"stats"`
+}
+
+type requestServerSettingsGetLinkQuality struct {
+	Token string `json:"token"`
+}
+
+type responseServerSettingsGetLinkQuality struct {
+	Quality []types.LinkQuality `json:"quality"`
+}
+
+type requestServerSettingsSetGameSettings struct {
+	Token    string             `json:"token"`
+	Settings types.GameSettings `json:"settings"`
+}
+
+// Formal exchange type, please do not delete.
+type responseServerSettingsSetGameSettings struct{}
+
+type requestServerSettingsGetGameSettings struct {
+	Token string `json:"token"`
+}
+
+type responseServerSettingsGetGameSettings struct {
+	Settings types.GameSettings `json:"settings"`
+}
+
+type requestServerSettingsJoinGroup struct {
+	Token string `json:"token"`
+	Name  string `json:"name"`
+}
+
+// Formal exchange type, please do not delete.
+type responseServerSettingsJoinGroup struct{}
+
+type requestServerSettingsLeaveGroup struct {
+	Token string `json:"token"`
+	Name  string `json:"name"`
+}
+
+// Formal exchange type, please do not delete.
+type responseServerSettingsLeaveGroup struct{}
+
+type requestServerSettingsGetGroups struct {
+	Token string `json:"token"`
+}
+
+type responseServerSettingsGetGroups struct {
+	Groups []types.Group `json:"groups"`
+}
+
+type requestServerSettingsListRooms struct {
+	Token     string                 `json:"token"`
+	ListRooms types.ListRoomsRequest `json:"listRooms"`
+}
+
+type responseServerSettingsListRooms struct {
+	Rooms types.RoomList `json:"rooms"`
+}
+
+type requestServerSettingsGetRoom struct {
+	Token string        `json:"token"`
+	Room  types.RoomKey `json:"room"`
+}
+
+type responseServerSettingsGetRoom struct {
+	RoomInfo types.RoomInfo `json:"roomInfo"`
+}
+
+type requestServerSettingsCloseRoom struct {
+	Token     string                 `json:"token"`
+	CloseRoom types.CloseRoomRequest `json:"closeRoom"`
+}
+
+// Formal exchange type, please do not delete.
+type responseServerSettingsCloseRoom struct{}
+
+type requestServerSettingsUpdateRoom struct {
+	Token      string                  `json:"token"`
+	UpdateRoom types.UpdateRoomRequest `json:"updateRoom"`
+}
+
+// Formal exchange type, please do not delete.
+type responseServerSettingsUpdateRoom struct{}
+
+type requestServerSettingsKickUser struct {
+	Token    string                `json:"token"`
+	KickUser types.KickUserRequest `json:"kickUser"`
+}
+
+// Formal exchange type, please do not delete.
+type responseServerSettingsKickUser struct{}
+
+type requestServerSettingsBanUser struct {
+	Token   string               `json:"token"`
+	BanUser types.BanUserRequest `json:"banUser"`
+}
+
+// Formal exchange type, please do not delete.
+type responseServerSettingsBanUser struct{}
+
+type requestServerSettingsGetRoomBans struct {
+	Token string        `json:"token"`
+	Room  types.RoomKey `json:"room"`
+}
+
+type responseServerSettingsGetRoomBans struct {
+	Bans []types.Ban `json:"bans"`
+}
+
+type requestServerSettingsGetGameBans struct {
+	Token string `json:"token"`
+}
+
+type responseServerSettingsGetGameBans struct {
+	Bans []types.Ban `json:"bans"`
+}
+
+type requestServerSettingsSetRoomMetadata struct {
+	Token           string                       `json:"token"`
+	SetRoomMetadata types.SetRoomMetadataRequest `json:"setRoomMetadata"`
+}
+
+type responseServerSettingsSetRoomMetadata struct {
+	Version uint64 `json:"version"`
+}
@@ -7,4 +7,20 @@ type fallbackServerSettings interface {
 	GetServerSettings(err error) bool
 	CreateRoom(err error) bool
 	GetDeletedRooms(err error) bool
+	GetRoomStats(err error) bool
+	GetLinkQuality(err error) bool
+	SetGameSettings(err error) bool
+	GetGameSettings(err error) bool
+	JoinGroup(err error) bool
+	LeaveGroup(err error) bool
+	GetGroups(err error) bool
+	ListRooms(err error) bool
+	GetRoom(err error) bool
+	CloseRoom(err error) bool
+	UpdateRoom(err error) bool
+	KickUser(err error) bool
+	BanUser(err error) bool
+	GetRoomBans(err error) bool
+	GetGameBans(err error) bool
+	SetRoomMetadata(err error) bool
 }
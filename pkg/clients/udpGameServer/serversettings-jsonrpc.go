@@ -18,6 +18,22 @@ type retServerSettingsHealthCheck = func(exists bool, err error)
 type retServerSettingsGetServerSettings = func(settings types.Settings, err error)
 type retServerSettingsCreateRoom = func(err error)
 type retServerSettingsGetDeletedRooms = func(deletedIds []types.GetDeletedRooms, err error)
+type retServerSettingsGetRoomStats = func(stats types.RoomStats, err error)
+type retServerSettingsGetLinkQuality = func(quality []types.LinkQuality, err error)
+type retServerSettingsSetGameSettings = func(err error)
+type retServerSettingsGetGameSettings = func(settings types.GameSettings, err error)
+type retServerSettingsJoinGroup = func(err error)
+type retServerSettingsLeaveGroup = func(err error)
+type retServerSettingsGetGroups = func(groups []types.Group, err error)
+type retServerSettingsListRooms = func(rooms types.RoomList, err error)
+type retServerSettingsGetRoom = func(roomInfo types.RoomInfo, err error)
+type retServerSettingsCloseRoom = func(err error)
+type retServerSettingsUpdateRoom = func(err error)
+type retServerSettingsKickUser = func(err error)
+type retServerSettingsBanUser = func(err error)
+type retServerSettingsGetRoomBans = func(bans []types.Ban, err error)
+type retServerSettingsGetGameBans = func(bans []types.Ban, err error)
+type retServerSettingsSetRoomMetadata = func(version uint64, err error)
 
 func (cli *ClientServerSettings) GetConnectionsNum(ctx context.Context, token string) (countConn int, exists bool, err error) {
 
@@ -295,3 +311,917 @@ func (cli *ClientServerSettings) ReqGetDeletedRooms(ctx context.Context, callbac
 	}
 	return
 }
+
+func (cli *ClientServerSettings) GetRoomStats(ctx context.Context, token string) (stats types.RoomStats, err error) {
+
+	request := requestServerSettingsGetRoomStats{Token: token}
+	var response responseServerSettingsGetRoomStats
+	var rpcResponse *jsonrpc.ResponseRPC
+	cacheKey, _ := hasher.Hash(request)
+	rpcResponse, err = cli.rpc.Call(ctx, "serversettings.getroomstats", request)
+	var fallbackCheck func(error) bool
+	if cli.fallbackServerSettings != nil {
+		fallbackCheck = cli.fallbackServerSettings.GetRoomStats
+	}
+	if rpcResponse != nil && rpcResponse.Error != nil {
+		if cli.errorDecoder != nil {
+			err = cli.errorDecoder(rpcResponse.Error.Raw())
+		} else {
+			err = fmt.Errorf(rpcResponse.Error.Message)
+		}
+	}
+	if err = cli.proceedResponse(ctx, err, cacheKey, fallbackCheck, rpcResponse, &response); err != nil {
+		return
+	}
+	return response.Stats, err
+}
+
+func (cli *ClientServerSettings) ReqGetRoomStats(ctx context.Context, callback retServerSettingsGetRoomStats, token string) (request RequestRPC) {
+
+	request = RequestRPC{rpcRequest: &jsonrpc.RequestRPC{
+		ID:      jsonrpc.NewID(),
+		JSONRPC: jsonrpc.Version,
+		Method:  "serversettings.getroomstats",
+		Params:  requestServerSettingsGetRoomStats{Token: token},
+	}}
+	if callback != nil {
+		var response responseServerSettingsGetRoomStats
+		request.retHandler = func(err error, rpcResponse *jsonrpc.ResponseRPC) {
+			cacheKey, _ := hasher.Hash(request.rpcRequest.Params)
+			var fallbackCheck func(error) bool
+			if cli.fallbackServerSettings != nil {
+				fallbackCheck = cli.fallbackServerSettings.GetRoomStats
+			}
+			if rpcResponse != nil && rpcResponse.Error != nil {
+				if cli.errorDecoder != nil {
+					err = cli.errorDecoder(rpcResponse.Error.Raw())
+				} else {
+					err = fmt.Errorf(rpcResponse.Error.Message)
+				}
+			}
+			callback(response.Stats, cli.proceedResponse(ctx, err, cacheKey, fallbackCheck, rpcResponse, &response))
+		}
+	}
+	return
+}
+
+func (cli *ClientServerSettings) GetLinkQuality(ctx context.Context, token string) (quality []types.LinkQuality, err error) {
+
+	request := requestServerSettingsGetLinkQuality{Token: token}
+	var response responseServerSettingsGetLinkQuality
+	var rpcResponse *jsonrpc.ResponseRPC
+	cacheKey, _ := hasher.Hash(request)
+	rpcResponse, err = cli.rpc.Call(ctx, "serversettings.getlinkquality", request)
+	var fallbackCheck func(error) bool
+	if cli.fallbackServerSettings != nil {
+		fallbackCheck = cli.fallbackServerSettings.GetLinkQuality
+	}
+	if rpcResponse != nil && rpcResponse.Error != nil {
+		if cli.errorDecoder != nil {
+			err = cli.errorDecoder(rpcResponse.Error.Raw())
+		} else {
+			err = fmt.Errorf(rpcResponse.Error.Message)
+		}
+	}
+	if err = cli.proceedResponse(ctx, err, cacheKey, fallbackCheck, rpcResponse, &response); err != nil {
+		return
+	}
+	return response.Quality, err
+}
+
+func (cli *ClientServerSettings) ReqGetLinkQuality(ctx context.Context, callback retServerSettingsGetLinkQuality, token string) (request RequestRPC) {
+
+	request = RequestRPC{rpcRequest: &jsonrpc.RequestRPC{
+		ID:      jsonrpc.NewID(),
+		JSONRPC: jsonrpc.Version,
+		Method:  "serversettings.getlinkquality",
+		Params:  requestServerSettingsGetLinkQuality{Token: token},
+	}}
+	if callback != nil {
+		var response responseServerSettingsGetLinkQuality
+		request.retHandler = func(err error, rpcResponse *jsonrpc.ResponseRPC) {
+			cacheKey, _ := hasher.Hash(request.rpcRequest.Params)
+			var fallbackCheck func(error) bool
+			if cli.fallbackServerSettings != nil {
+				fallbackCheck = cli.fallbackServerSettings.GetLinkQuality
+			}
+			if rpcResponse != nil && rpcResponse.Error != nil {
+				if cli.errorDecoder != nil {
+					err = cli.errorDecoder(rpcResponse.Error.Raw())
+				} else {
+					err = fmt.Errorf(rpcResponse.Error.Message)
+				}
+			}
+			callback(response.Quality, cli.proceedResponse(ctx, err, cacheKey, fallbackCheck, rpcResponse, &response))
+		}
+	}
+	return
+}
+
+func (cli *ClientServerSettings) SetGameSettings(ctx context.Context, token string, settings types.GameSettings) (err error) {
+
+	request := requestServerSettingsSetGameSettings{
+		Settings: settings,
+		Token:    token,
+	}
+	var response responseServerSettingsSetGameSettings
+	var rpcResponse *jsonrpc.ResponseRPC
+	cacheKey, _ := hasher.Hash(request)
+	rpcResponse, err = cli.rpc.Call(ctx, "serversettings.setgamesettings", request)
+	var fallbackCheck func(error) bool
+	if cli.fallbackServerSettings != nil {
+		fallbackCheck = cli.fallbackServerSettings.SetGameSettings
+	}
+	if rpcResponse != nil && rpcResponse.Error != nil {
+		if cli.errorDecoder != nil {
+			err = cli.errorDecoder(rpcResponse.Error.Raw())
+		} else {
+			err = fmt.Errorf(rpcResponse.Error.Message)
+		}
+	}
+	if err = cli.proceedResponse(ctx, err, cacheKey, fallbackCheck, rpcResponse, &response); err != nil {
+		return
+	}
+	return err
+}
+
+func (cli *ClientServerSettings) ReqSetGameSettings(ctx context.Context, callback retServerSettingsSetGameSettings, token string, settings types.GameSettings) (request RequestRPC) {
+
+	request = RequestRPC{rpcRequest: &jsonrpc.RequestRPC{
+		ID:      jsonrpc.NewID(),
+		JSONRPC: jsonrpc.Version,
+		Method:  "serversettings.setgamesettings",
+		Params: requestServerSettingsSetGameSettings{
+			Settings: settings,
+			Token:    token,
+		},
+	}}
+	if callback != nil {
+		var response responseServerSettingsSetGameSettings
+		request.retHandler = func(err error, rpcResponse *jsonrpc.ResponseRPC) {
+			cacheKey, _ := hasher.Hash(request.rpcRequest.Params)
+			var fallbackCheck func(error) bool
+			if cli.fallbackServerSettings != nil {
+				fallbackCheck = cli.fallbackServerSettings.SetGameSettings
+			}
+			if rpcResponse != nil && rpcResponse.Error != nil {
+				if cli.errorDecoder != nil {
+					err = cli.errorDecoder(rpcResponse.Error.Raw())
+				} else {
+					err = fmt.Errorf(rpcResponse.Error.Message)
+				}
+			}
+			callback(cli.proceedResponse(ctx, err, cacheKey, fallbackCheck, rpcResponse, &response))
+		}
+	}
+	return
+}
+
+func (cli *ClientServerSettings) GetGameSettings(ctx context.Context, token string) (settings types.GameSettings, err error) {
+
+	request := requestServerSettingsGetGameSettings{Token: token}
+	var response responseServerSettingsGetGameSettings
+	var rpcResponse *jsonrpc.ResponseRPC
+	cacheKey, _ := hasher.Hash(request)
+	rpcResponse, err = cli.rpc.Call(ctx, "serversettings.getgamesettings", request)
+	var fallbackCheck func(error) bool
+	if cli.fallbackServerSettings != nil {
+		fallbackCheck = cli.fallbackServerSettings.GetGameSettings
+	}
+	if rpcResponse != nil && rpcResponse.Error != nil {
+		if cli.errorDecoder != nil {
+			err = cli.errorDecoder(rpcResponse.Error.Raw())
+		} else {
+			err = fmt.Errorf(rpcResponse.Error.Message)
+		}
+	}
+	if err = cli.proceedResponse(ctx, err, cacheKey, fallbackCheck, rpcResponse, &response); err != nil {
+		return
+	}
+	return response.Settings, err
+}
+
+func (cli *ClientServerSettings) ReqGetGameSettings(ctx context.Context, callback retServerSettingsGetGameSettings, token string) (request RequestRPC) {
+
+	request = RequestRPC{rpcRequest: &jsonrpc.RequestRPC{
+		ID:      jsonrpc.NewID(),
+		JSONRPC: jsonrpc.Version,
+		Method:  "serversettings.getgamesettings",
+		Params:  requestServerSettingsGetGameSettings{Token: token},
+	}}
+	if callback != nil {
+		var response responseServerSettingsGetGameSettings
+		request.retHandler = func(err error, rpcResponse *jsonrpc.ResponseRPC) {
+			cacheKey, _ := hasher.Hash(request.rpcRequest.Params)
+			var fallbackCheck func(error) bool
+			if cli.fallbackServerSettings != nil {
+				fallbackCheck = cli.fallbackServerSettings.GetGameSettings
+			}
+			if rpcResponse != nil && rpcResponse.Error != nil {
+				if cli.errorDecoder != nil {
+					err = cli.errorDecoder(rpcResponse.Error.Raw())
+				} else {
+					err = fmt.Errorf(rpcResponse.Error.Message)
+				}
+			}
+			callback(response.Settings, cli.proceedResponse(ctx, err, cacheKey, fallbackCheck, rpcResponse, &response))
+		}
+	}
+	return
+}
+
+func (cli *ClientServerSettings) JoinGroup(ctx context.Context, token string, name string) (err error) {
+
+	request := requestServerSettingsJoinGroup{
+		Name:  name,
+		Token: token,
+	}
+	var response responseServerSettingsJoinGroup
+	var rpcResponse *jsonrpc.ResponseRPC
+	cacheKey, _ := hasher.Hash(request)
+	rpcResponse, err = cli.rpc.Call(ctx, "serversettings.joingroup", request)
+	var fallbackCheck func(error) bool
+	if cli.fallbackServerSettings != nil {
+		fallbackCheck = cli.fallbackServerSettings.JoinGroup
+	}
+	if rpcResponse != nil && rpcResponse.Error != nil {
+		if cli.errorDecoder != nil {
+			err = cli.errorDecoder(rpcResponse.Error.Raw())
+		} else {
+			err = fmt.Errorf(rpcResponse.Error.Message)
+		}
+	}
+	if err = cli.proceedResponse(ctx, err, cacheKey, fallbackCheck, rpcResponse, &response); err != nil {
+		return
+	}
+	return err
+}
+
+func (cli *ClientServerSettings) ReqJoinGroup(ctx context.Context, callback retServerSettingsJoinGroup, token string, name string) (request RequestRPC) {
+
+	request = RequestRPC{rpcRequest: &jsonrpc.RequestRPC{
+		ID:      jsonrpc.NewID(),
+		JSONRPC: jsonrpc.Version,
+		Method:  "serversettings.joingroup",
+		Params: requestServerSettingsJoinGroup{
+			Name:  name,
+			Token: token,
+		},
+	}}
+	if callback != nil {
+		var response responseServerSettingsJoinGroup
+		request.retHandler = func(err error, rpcResponse *jsonrpc.ResponseRPC) {
+			cacheKey, _ := hasher.Hash(request.rpcRequest.Params)
+			var fallbackCheck func(error) bool
+			if cli.fallbackServerSettings != nil {
+				fallbackCheck = cli.fallbackServerSettings.JoinGroup
+			}
+			if rpcResponse != nil && rpcResponse.Error != nil {
+				if cli.errorDecoder != nil {
+					err = cli.errorDecoder(rpcResponse.Error.Raw())
+				} else {
+					err = fmt.Errorf(rpcResponse.Error.Message)
+				}
+			}
+			callback(cli.proceedResponse(ctx, err, cacheKey, fallbackCheck, rpcResponse, &response))
+		}
+	}
+	return
+}
+
+func (cli *ClientServerSettings) LeaveGroup(ctx context.Context, token string, name string) (err error) {
+
+	request := requestServerSettingsLeaveGroup{
+		Name:  name,
+		Token: token,
+	}
+	var response responseServerSettingsLeaveGroup
+	var rpcResponse *jsonrpc.ResponseRPC
+	cacheKey, _ := hasher.Hash(request)
+	rpcResponse, err = cli.rpc.Call(ctx, "serversettings.leavegroup", request)
+	var fallbackCheck func(error) bool
+	if cli.fallbackServerSettings != nil {
+		fallbackCheck = cli.fallbackServerSettings.LeaveGroup
+	}
+	if rpcResponse != nil && rpcResponse.Error != nil {
+		if cli.errorDecoder != nil {
+			err = cli.errorDecoder(rpcResponse.Error.Raw())
+		} else {
+			err = fmt.Errorf(rpcResponse.Error.Message)
+		}
+	}
+	if err = cli.proceedResponse(ctx, err, cacheKey, fallbackCheck, rpcResponse, &response); err != nil {
+		return
+	}
+	return err
+}
+
+func (cli *ClientServerSettings) ReqLeaveGroup(ctx context.Context, callback retServerSettingsLeaveGroup, token string, name string) (request RequestRPC) {
+
+	request = RequestRPC{rpcRequest: &jsonrpc.RequestRPC{
+		ID:      jsonrpc.NewID(),
+		JSONRPC: jsonrpc.Version,
+		Method:  "serversettings.leavegroup",
+		Params: requestServerSettingsLeaveGroup{
+			Name:  name,
+			Token: token,
+		},
+	}}
+	if callback != nil {
+		var response responseServerSettingsLeaveGroup
+		request.retHandler = func(err error, rpcResponse *jsonrpc.ResponseRPC) {
+			cacheKey, _ := hasher.Hash(request.rpcRequest.Params)
+			var fallbackCheck func(error) bool
+			if cli.fallbackServerSettings != nil {
+				fallbackCheck = cli.fallbackServerSettings.LeaveGroup
+			}
+			if rpcResponse != nil && rpcResponse.Error != nil {
+				if cli.errorDecoder != nil {
+					err = cli.errorDecoder(rpcResponse.Error.Raw())
+				} else {
+					err = fmt.Errorf(rpcResponse.Error.Message)
+				}
+			}
+			callback(cli.proceedResponse(ctx, err, cacheKey, fallbackCheck, rpcResponse, &response))
+		}
+	}
+	return
+}
+
+func (cli *ClientServerSettings) GetGroups(ctx context.Context, token string) (groups []types.Group, err error) {
+
+	request := requestServerSettingsGetGroups{Token: token}
+	var response responseServerSettingsGetGroups
+	var rpcResponse *jsonrpc.ResponseRPC
+	cacheKey, _ := hasher.Hash(request)
+	rpcResponse, err = cli.rpc.Call(ctx, "serversettings.getgroups", request)
+	var fallbackCheck func(error) bool
+	if cli.fallbackServerSettings != nil {
+		fallbackCheck = cli.fallbackServerSettings.GetGroups
+	}
+	if rpcResponse != nil && rpcResponse.Error != nil {
+		if cli.errorDecoder != nil {
+			err = cli.errorDecoder(rpcResponse.Error.Raw())
+		} else {
+			err = fmt.Errorf(rpcResponse.Error.Message)
+		}
+	}
+	if err = cli.proceedResponse(ctx, err, cacheKey, fallbackCheck, rpcResponse, &response); err != nil {
+		return
+	}
+	return response.Groups, err
+}
+
+func (cli *ClientServerSettings) ReqGetGroups(ctx context.Context, callback retServerSettingsGetGroups, token string) (request RequestRPC) {
+
+	request = RequestRPC{rpcRequest: &jsonrpc.RequestRPC{
+		ID:      jsonrpc.NewID(),
+		JSONRPC: jsonrpc.Version,
+		Method:  "serversettings.getgroups",
+		Params:  requestServerSettingsGetGroups{Token: token},
+	}}
+	if callback != nil {
+		var response responseServerSettingsGetGroups
+		request.retHandler = func(err error, rpcResponse *jsonrpc.ResponseRPC) {
+			cacheKey, _ := hasher.Hash(request.rpcRequest.Params)
+			var fallbackCheck func(error) bool
+			if cli.fallbackServerSettings != nil {
+				fallbackCheck = cli.fallbackServerSettings.GetGroups
+			}
+			if rpcResponse != nil && rpcResponse.Error != nil {
+				if cli.errorDecoder != nil {
+					err = cli.errorDecoder(rpcResponse.Error.Raw())
+				} else {
+					err = fmt.Errorf(rpcResponse.Error.Message)
+				}
+			}
+			callback(response.Groups, cli.proceedResponse(ctx, err, cacheKey, fallbackCheck, rpcResponse, &response))
+		}
+	}
+	return
+}
+
+func (cli *ClientServerSettings) ListRooms(ctx context.Context, token string, listRooms types.ListRoomsRequest) (rooms types.RoomList, err error) {
+
+	request := requestServerSettingsListRooms{
+		ListRooms: listRooms,
+		Token:     token,
+	}
+	var response responseServerSettingsListRooms
+	var rpcResponse *jsonrpc.ResponseRPC
+	cacheKey, _ := hasher.Hash(request)
+	rpcResponse, err = cli.rpc.Call(ctx, "serversettings.listrooms", request)
+	var fallbackCheck func(error) bool
+	if cli.fallbackServerSettings != nil {
+		fallbackCheck = cli.fallbackServerSettings.ListRooms
+	}
+	if rpcResponse != nil && rpcResponse.Error != nil {
+		if cli.errorDecoder != nil {
+			err = cli.errorDecoder(rpcResponse.Error.Raw())
+		} else {
+			err = fmt.Errorf(rpcResponse.Error.Message)
+		}
+	}
+	if err = cli.proceedResponse(ctx, err, cacheKey, fallbackCheck, rpcResponse, &response); err != nil {
+		return
+	}
+	return response.Rooms, err
+}
+
+func (cli *ClientServerSettings) ReqListRooms(ctx context.Context, callback retServerSettingsListRooms, token string, listRooms types.ListRoomsRequest) (request RequestRPC) {
+
+	request = RequestRPC{rpcRequest: &jsonrpc.RequestRPC{
+		ID:      jsonrpc.NewID(),
+		JSONRPC: jsonrpc.Version,
+		Method:  "serversettings.listrooms",
+		Params: requestServerSettingsListRooms{
+			ListRooms: listRooms,
+			Token:     token,
+		},
+	}}
+	if callback != nil {
+		var response responseServerSettingsListRooms
+		request.retHandler = func(err error, rpcResponse *jsonrpc.ResponseRPC) {
+			cacheKey, _ := hasher.Hash(request.rpcRequest.Params)
+			var fallbackCheck func(error) bool
+			if cli.fallbackServerSettings != nil {
+				fallbackCheck = cli.fallbackServerSettings.ListRooms
+			}
+			if rpcResponse != nil && rpcResponse.Error != nil {
+				if cli.errorDecoder != nil {
+					err = cli.errorDecoder(rpcResponse.Error.Raw())
+				} else {
+					err = fmt.Errorf(rpcResponse.Error.Message)
+				}
+			}
+			callback(response.Rooms, cli.proceedResponse(ctx, err, cacheKey, fallbackCheck, rpcResponse, &response))
+		}
+	}
+	return
+}
+
+func (cli *ClientServerSettings) GetRoom(ctx context.Context, token string, room types.RoomKey) (roomInfo types.RoomInfo, err error) {
+
+	request := requestServerSettingsGetRoom{
+		Room:  room,
+		Token: token,
+	}
+	var response responseServerSettingsGetRoom
+	var rpcResponse *jsonrpc.ResponseRPC
+	cacheKey, _ := hasher.Hash(request)
+	rpcResponse, err = cli.rpc.Call(ctx, "serversettings.getroom", request)
+	var fallbackCheck func(error) bool
+	if cli.fallbackServerSettings != nil {
+		fallbackCheck = cli.fallbackServerSettings.GetRoom
+	}
+	if rpcResponse != nil && rpcResponse.Error != nil {
+		if cli.errorDecoder != nil {
+			err = cli.errorDecoder(rpcResponse.Error.Raw())
+		} else {
+			err = fmt.Errorf(rpcResponse.Error.Message)
+		}
+	}
+	if err = cli.proceedResponse(ctx, err, cacheKey, fallbackCheck, rpcResponse, &response); err != nil {
+		return
+	}
+	return response.RoomInfo, err
+}
+
+func (cli *ClientServerSettings) ReqGetRoom(ctx context.Context, callback retServerSettingsGetRoom, token string, room types.RoomKey) (request RequestRPC) {
+
+	request = RequestRPC{rpcRequest: &jsonrpc.RequestRPC{
+		ID:      jsonrpc.NewID(),
+		JSONRPC: jsonrpc.Version,
+		Method:  "serversettings.getroom",
+		Params: requestServerSettingsGetRoom{
+			Room:  room,
+			Token: token,
+		},
+	}}
+	if callback != nil {
+		var response responseServerSettingsGetRoom
+		request.retHandler = func(err error, rpcResponse *jsonrpc.ResponseRPC) {
+			cacheKey, _ := hasher.Hash(request.rpcRequest.Params)
+			var fallbackCheck func(error) bool
+			if cli.fallbackServerSettings != nil {
+				fallbackCheck = cli.fallbackServerSettings.GetRoom
+			}
+			if rpcResponse != nil && rpcResponse.Error != nil {
+				if cli.errorDecoder != nil {
+					err = cli.errorDecoder(rpcResponse.Error.Raw())
+				} else {
+					err = fmt.Errorf(rpcResponse.Error.Message)
+				}
+			}
+			callback(response.RoomInfo, cli.proceedResponse(ctx, err, cacheKey, fallbackCheck, rpcResponse, &response))
+		}
+	}
+	return
+}
+
+func (cli *ClientServerSettings) CloseRoom(ctx context.Context, token string, closeRoom types.CloseRoomRequest) (err error) {
+
+	request := requestServerSettingsCloseRoom{
+		CloseRoom: closeRoom,
+		Token:     token,
+	}
+	var response responseServerSettingsCloseRoom
+	var rpcResponse *jsonrpc.ResponseRPC
+	cacheKey, _ := hasher.Hash(request)
+	rpcResponse, err = cli.rpc.Call(ctx, "serversettings.closeroom", request)
+	var fallbackCheck func(error) bool
+	if cli.fallbackServerSettings != nil {
+		fallbackCheck = cli.fallbackServerSettings.CloseRoom
+	}
+	if rpcResponse != nil && rpcResponse.Error != nil {
+		if cli.errorDecoder != nil {
+			err = cli.errorDecoder(rpcResponse.Error.Raw())
+		} else {
+			err = fmt.Errorf(rpcResponse.Error.Message)
+		}
+	}
+	if err = cli.proceedResponse(ctx, err, cacheKey, fallbackCheck, rpcResponse, &response); err != nil {
+		return
+	}
+	return err
+}
+
+func (cli *ClientServerSettings) ReqCloseRoom(ctx context.Context, callback retServerSettingsCloseRoom, token string, closeRoom types.CloseRoomRequest) (request RequestRPC) {
+
+	request = RequestRPC{rpcRequest: &jsonrpc.RequestRPC{
+		ID:      jsonrpc.NewID(),
+		JSONRPC: jsonrpc.Version,
+		Method:  "serversettings.closeroom",
+		Params: requestServerSettingsCloseRoom{
+			CloseRoom: closeRoom,
+			Token:     token,
+		},
+	}}
+	if callback != nil {
+		var response responseServerSettingsCloseRoom
+		request.retHandler = func(err error, rpcResponse *jsonrpc.ResponseRPC) {
+			cacheKey, _ := hasher.Hash(request.rpcRequest.Params)
+			var fallbackCheck func(error) bool
+			if cli.fallbackServerSettings != nil {
+				fallbackCheck = cli.fallbackServerSettings.CloseRoom
+			}
+			if rpcResponse != nil && rpcResponse.Error != nil {
+				if cli.errorDecoder != nil {
+					err = cli.errorDecoder(rpcResponse.Error.Raw())
+				} else {
+					err = fmt.Errorf(rpcResponse.Error.Message)
+				}
+			}
+			callback(cli.proceedResponse(ctx, err, cacheKey, fallbackCheck, rpcResponse, &response))
+		}
+	}
+	return
+}
+
+func (cli *ClientServerSettings) UpdateRoom(ctx context.Context, token string, updateRoom types.UpdateRoomRequest) (err error) {
+
+	request := requestServerSettingsUpdateRoom{
+		Token:      token,
+		UpdateRoom: updateRoom,
+	}
+	var response responseServerSettingsUpdateRoom
+	var rpcResponse *jsonrpc.ResponseRPC
+	cacheKey, _ := hasher.Hash(request)
+	rpcResponse, err = cli.rpc.Call(ctx, "serversettings.updateroom", request)
+	var fallbackCheck func(error) bool
+	if cli.fallbackServerSettings != nil {
+		fallbackCheck = cli.fallbackServerSettings.UpdateRoom
+	}
+	if rpcResponse != nil && rpcResponse.Error != nil {
+		if cli.errorDecoder != nil {
+			err = cli.errorDecoder(rpcResponse.Error.Raw())
+		} else {
+			err = fmt.Errorf(rpcResponse.Error.Message)
+		}
+	}
+	if err = cli.proceedResponse(ctx, err, cacheKey, fallbackCheck, rpcResponse, &response); err != nil {
+		return
+	}
+	return err
+}
+
+func (cli *ClientServerSettings) ReqUpdateRoom(ctx context.Context, callback retServerSettingsUpdateRoom, token string, updateRoom types.UpdateRoomRequest) (request RequestRPC) {
+
+	request = RequestRPC{rpcRequest: &jsonrpc.RequestRPC{
+		ID:      jsonrpc.NewID(),
+		JSONRPC: jsonrpc.Version,
+		Method:  "serversettings.updateroom",
+		Params: requestServerSettingsUpdateRoom{
+			Token:      token,
+			UpdateRoom: updateRoom,
+		},
+	}}
+	if callback != nil {
+		var response responseServerSettingsUpdateRoom
+		request.retHandler = func(err error, rpcResponse *jsonrpc.ResponseRPC) {
+			cacheKey, _ := hasher.Hash(request.rpcRequest.Params)
+			var fallbackCheck func(error) bool
+			if cli.fallbackServerSettings != nil {
+				fallbackCheck = cli.fallbackServerSettings.UpdateRoom
+			}
+			if rpcResponse != nil && rpcResponse.Error != nil {
+				if cli.errorDecoder != nil {
+					err = cli.errorDecoder(rpcResponse.Error.Raw())
+				} else {
+					err = fmt.Errorf(rpcResponse.Error.Message)
+				}
+			}
+			callback(cli.proceedResponse(ctx, err, cacheKey, fallbackCheck, rpcResponse, &response))
+		}
+	}
+	return
+}
+
+func (cli *ClientServerSettings) KickUser(ctx context.Context, token string, kickUser types.KickUserRequest) (err error) {
+
+	request := requestServerSettingsKickUser{
+		KickUser: kickUser,
+		Token:    token,
+	}
+	var response responseServerSettingsKickUser
+	var rpcResponse *jsonrpc.ResponseRPC
+	cacheKey, _ := hasher.Hash(request)
+	rpcResponse, err = cli.rpc.Call(ctx, "serversettings.kickuser", request)
+	var fallbackCheck func(error) bool
+	if cli.fallbackServerSettings != nil {
+		fallbackCheck = cli.fallbackServerSettings.KickUser
+	}
+	if rpcResponse != nil && rpcResponse.Error != nil {
+		if cli.errorDecoder != nil {
+			err = cli.errorDecoder(rpcResponse.Error.Raw())
+		} else {
+			err = fmt.Errorf(rpcResponse.Error.Message)
+		}
+	}
+	if err = cli.proceedResponse(ctx, err, cacheKey, fallbackCheck, rpcResponse, &response); err != nil {
+		return
+	}
+	return err
+}
+
+func (cli *ClientServerSettings) ReqKickUser(ctx context.Context, callback retServerSettingsKickUser, token string, kickUser types.KickUserRequest) (request RequestRPC) {
+
+	request = RequestRPC{rpcRequest: &jsonrpc.RequestRPC{
+		ID:      jsonrpc.NewID(),
+		JSONRPC: jsonrpc.Version,
+		Method:  "serversettings.kickuser",
+		Params: requestServerSettingsKickUser{
+			KickUser: kickUser,
+			Token:    token,
+		},
+	}}
+	if callback != nil {
+		var response responseServerSettingsKickUser
+		request.retHandler = func(err error, rpcResponse *jsonrpc.ResponseRPC) {
+			cacheKey, _ := hasher.Hash(request.rpcRequest.Params)
+			var fallbackCheck func(error) bool
+			if cli.fallbackServerSettings != nil {
+				fallbackCheck = cli.fallbackServerSettings.KickUser
+			}
+			if rpcResponse != nil && rpcResponse.Error != nil {
+				if cli.errorDecoder != nil {
+					err = cli.errorDecoder(rpcResponse.Error.Raw())
+				} else {
+					err = fmt.Errorf(rpcResponse.Error.Message)
+				}
+			}
+			callback(cli.proceedResponse(ctx, err, cacheKey, fallbackCheck, rpcResponse, &response))
+		}
+	}
+	return
+}
+
+func (cli *ClientServerSettings) BanUser(ctx context.Context, token string, banUser types.BanUserRequest) (err error) {
+
+	request := requestServerSettingsBanUser{
+		BanUser: banUser,
+		Token:   token,
+	}
+	var response responseServerSettingsBanUser
+	var rpcResponse *jsonrpc.ResponseRPC
+	cacheKey, _ := hasher.Hash(request)
+	rpcResponse, err = cli.rpc.Call(ctx, "serversettings.banuser", request)
+	var fallbackCheck func(error) bool
+	if cli.fallbackServerSettings != nil {
+		fallbackCheck = cli.fallbackServerSettings.BanUser
+	}
+	if rpcResponse != nil && rpcResponse.Error != nil {
+		if cli.errorDecoder != nil {
+			err = cli.errorDecoder(rpcResponse.Error.Raw())
+		} else {
+			err = fmt.Errorf(rpcResponse.Error.Message)
+		}
+	}
+	if err = cli.proceedResponse(ctx, err, cacheKey, fallbackCheck, rpcResponse, &response); err != nil {
+		return
+	}
+	return err
+}
+
+func (cli *ClientServerSettings) ReqBanUser(ctx context.Context, callback retServerSettingsBanUser, token string, banUser types.BanUserRequest) (request RequestRPC) {
+
+	request = RequestRPC{rpcRequest: &jsonrpc.RequestRPC{
+		ID:      jsonrpc.NewID(),
+		JSONRPC: jsonrpc.Version,
+		Method:  "serversettings.banuser",
+		Params: requestServerSettingsBanUser{
+			BanUser: banUser,
+			Token:   token,
+		},
+	}}
+	if callback != nil {
+		var response responseServerSettingsBanUser
+		request.retHandler = func(err error, rpcResponse *jsonrpc.ResponseRPC) {
+			cacheKey, _ := hasher.Hash(request.rpcRequest.Params)
+			var fallbackCheck func(error) bool
+			if cli.fallbackServerSettings != nil {
+				fallbackCheck = cli.fallbackServerSettings.BanUser
+			}
+			if rpcResponse != nil && rpcResponse.Error != nil {
+				if cli.errorDecoder != nil {
+					err = cli.errorDecoder(rpcResponse.Error.Raw())
+				} else {
+					err = fmt.Errorf(rpcResponse.Error.Message)
+				}
+			}
+			callback(cli.proceedResponse(ctx, err, cacheKey, fallbackCheck, rpcResponse, &response))
+		}
+	}
+	return
+}
+
+func (cli *ClientServerSettings) GetRoomBans(ctx context.Context, token string, room types.RoomKey) (bans []types.Ban, err error) {
+
+	request := requestServerSettingsGetRoomBans{
+		Room:  room,
+		Token: token,
+	}
+	var response responseServerSettingsGetRoomBans
+	var rpcResponse *jsonrpc.ResponseRPC
+	cacheKey, _ := hasher.Hash(request)
+	rpcResponse, err = cli.rpc.Call(ctx, "serversettings.getroombans", request)
+	var fallbackCheck func(error) bool
+	if cli.fallbackServerSettings != nil {
+		fallbackCheck = cli.fallbackServerSettings.GetRoomBans
+	}
+	if rpcResponse != nil && rpcResponse.Error != nil {
+		if cli.errorDecoder != nil {
+			err = cli.errorDecoder(rpcResponse.Error.Raw())
+		} else {
+			err = fmt.Errorf(rpcResponse.Error.Message)
+		}
+	}
+	if err = cli.proceedResponse(ctx, err, cacheKey, fallbackCheck, rpcResponse, &response); err != nil {
+		return
+	}
+	return response.Bans, err
+}
+
+func (cli *ClientServerSettings) ReqGetRoomBans(ctx context.Context, callback retServerSettingsGetRoomBans, token string, room types.RoomKey) (request RequestRPC) {
+
+	request = RequestRPC{rpcRequest: &jsonrpc.RequestRPC{
+		ID:      jsonrpc.NewID(),
+		JSONRPC: jsonrpc.Version,
+		Method:  "serversettings.getroombans",
+		Params: requestServerSettingsGetRoomBans{
+			Room:  room,
+			Token: token,
+		},
+	}}
+	if callback != nil {
+		var response responseServerSettingsGetRoomBans
+		request.retHandler = func(err error, rpcResponse *jsonrpc.ResponseRPC) {
+			cacheKey, _ := hasher.Hash(request.rpcRequest.Params)
+			var fallbackCheck func(error) bool
+			if cli.fallbackServerSettings != nil {
+				fallbackCheck = cli.fallbackServerSettings.GetRoomBans
+			}
+			if rpcResponse != nil && rpcResponse.Error != nil {
+				if cli.errorDecoder != nil {
+					err = cli.errorDecoder(rpcResponse.Error.Raw())
+				} else {
+					err = fmt.Errorf(rpcResponse.Error.Message)
+				}
+			}
+			callback(response.Bans, cli.proceedResponse(ctx, err, cacheKey, fallbackCheck, rpcResponse, &response))
+		}
+	}
+	return
+}
+
+func (cli *ClientServerSettings) GetGameBans(ctx context.Context, token string) (bans []types.Ban, err error) {
+
+	request := requestServerSettingsGetGameBans{Token: token}
+	var response responseServerSettingsGetGameBans
+	var rpcResponse *jsonrpc.ResponseRPC
+	cacheKey, _ := hasher.Hash(request)
+	rpcResponse, err = cli.rpc.Call(ctx, "serversettings.getgamebans", request)
+	var fallbackCheck func(error) bool
+	if cli.fallbackServerSettings != nil {
+		fallbackCheck = cli.fallbackServerSettings.GetGameBans
+	}
+	if rpcResponse != nil && rpcResponse.Error != nil {
+		if cli.errorDecoder != nil {
+			err = cli.errorDecoder(rpcResponse.Error.Raw())
+		} else {
+			err = fmt.Errorf(rpcResponse.Error.Message)
+		}
+	}
+	if err = cli.proceedResponse(ctx, err, cacheKey, fallbackCheck, rpcResponse, &response); err != nil {
+		return
+	}
+	return response.Bans, err
+}
+
+func (cli *ClientServerSettings) ReqGetGameBans(ctx context.Context, callback retServerSettingsGetGameBans, token string) (request RequestRPC) {
+
+	request = RequestRPC{rpcRequest: &jsonrpc.RequestRPC{
+		ID:      jsonrpc.NewID(),
+		JSONRPC: jsonrpc.Version,
+		Method:  "serversettings.getgamebans",
+		Params:  requestServerSettingsGetGameBans{Token: token},
+	}}
+	if callback != nil {
+		var response responseServerSettingsGetGameBans
+		request.retHandler = func(err error, rpcResponse *jsonrpc.ResponseRPC) {
+			cacheKey, _ := hasher.Hash(request.rpcRequest.Params)
+			var fallbackCheck func(error) bool
+			if cli.fallbackServerSettings != nil {
+				fallbackCheck = cli.fallbackServerSettings.GetGameBans
+			}
+			if rpcResponse != nil && rpcResponse.Error != nil {
+				if cli.errorDecoder != nil {
+					err = cli.errorDecoder(rpcResponse.Error.Raw())
+				} else {
+					err = fmt.Errorf(rpcResponse.Error.Message)
+				}
+			}
+			callback(response.Bans, cli.proceedResponse(ctx, err, cacheKey, fallbackCheck, rpcResponse, &response))
+		}
+	}
+	return
+}
+
+func (cli *ClientServerSettings) SetRoomMetadata(ctx context.Context, token string, setRoomMetadata types.SetRoomMetadataRequest) (version uint64, err error) {
+
+	request := requestServerSettingsSetRoomMetadata{
+		SetRoomMetadata: setRoomMetadata,
+		Token:           token,
+	}
+	var response responseServerSettingsSetRoomMetadata
+	var rpcResponse *jsonrpc.ResponseRPC
+	cacheKey, _ := hasher.Hash(request)
+	rpcResponse, err = cli.rpc.Call(ctx, "serversettings.setroommetadata", request)
+	var fallbackCheck func(error) bool
+	if cli.fallbackServerSettings != nil {
+		fallbackCheck = cli.fallbackServerSettings.SetRoomMetadata
+	}
+	if rpcResponse != nil && rpcResponse.Error != nil {
+		if cli.errorDecoder != nil {
+			err = cli.errorDecoder(rpcResponse.Error.Raw())
+		} else {
+			err = fmt.Errorf(rpcResponse.Error.Message)
+		}
+	}
+	if err = cli.proceedResponse(ctx, err, cacheKey, fallbackCheck, rpcResponse, &response); err != nil {
+		return
+	}
+	return response.Version, err
+}
+
+func (cli *ClientServerSettings) ReqSetRoomMetadata(ctx context.Context, callback retServerSettingsSetRoomMetadata, token string, setRoomMetadata types.SetRoomMetadataRequest) (request RequestRPC) {
+
+	request = RequestRPC{rpcRequest: &jsonrpc.RequestRPC{
+		ID:      jsonrpc.NewID(),
+		JSONRPC: jsonrpc.Version,
+		Method:  "serversettings.setroommetadata",
+		Params: requestServerSettingsSetRoomMetadata{
+			SetRoomMetadata: setRoomMetadata,
+			Token:           token,
+		},
+	}}
+	if callback != nil {
+		var response responseServerSettingsSetRoomMetadata
+		request.retHandler = func(err error, rpcResponse *jsonrpc.ResponseRPC) {
+			cacheKey, _ := hasher.Hash(request.rpcRequest.Params)
+			var fallbackCheck func(error) bool
+			if cli.fallbackServerSettings != nil {
+				fallbackCheck = cli.fallbackServerSettings.SetRoomMetadata
+			}
+			if rpcResponse != nil && rpcResponse.Error != nil {
+				if cli.errorDecoder != nil {
+					err = cli.errorDecoder(rpcResponse.Error.Raw())
+				} else {
+					err = fmt.Errorf(rpcResponse.Error.Message)
+				}
+			}
+			callback(response.Version, cli.proceedResponse(ctx, err, cacheKey, fallbackCheck, rpcResponse, &response))
+		}
+	}
+	return
+}
@@ -0,0 +1,34 @@
+package errors
+
+import "errors"
+
+// Code identifies the errors that are reported to clients on the wire. Codes
+// are stable: a code never changes its meaning, and new errors get new codes.
+type Code uint8
+
+const (
+	CodeUnknown Code = iota
+	CodeRoomFull
+	CodeRoomNotFound
+	CodeNotInvited
+	CodeBanned
+	CodeRoomLocked
+)
+
+var codes = map[error]Code{
+	ErrRoomFull:     CodeRoomFull,
+	ErrRoomNotFound: CodeRoomNotFound,
+	ErrNotInvited:   CodeNotInvited,
+	ErrBanned:       CodeBanned,
+	ErrRoomLocked:   CodeRoomLocked,
+}
+
+// CodeOf returns the code of err, or CodeUnknown when err has none.
+func CodeOf(err error) Code {
+	for target, code := range codes {
+		if errors.Is(err, target) {
+			return code
+		}
+	}
+	return CodeUnknown
+}
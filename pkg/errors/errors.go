@@ -14,4 +14,57 @@ var (
 	ErrNotifyServerNotValid      = errors.New("err notify server not valid")
 	ErrGameConfigMarshalUserData = errors.New("err game config marshal user data")
 	ErrGameResultsNotFound       = errors.New("game results not found")
+	ErrPacketTooShort            = errors.New("packet too short")
+	ErrPacketBadMagic            = errors.New("packet bad magic")
+	ErrPacketBadVersion          = errors.New("packet unsupported protocol version")
+	ErrPacketBadType             = errors.New("packet unknown message type")
+	ErrLegacyProtocolDisabled    = errors.New("legacy protocol disabled")
+	ErrSessionNotFound           = errors.New("session not found")
+	ErrSessionBadMAC             = errors.New("session packet bad mac")
+	ErrPacketBadAck              = errors.New("packet bad ack payload")
+	ErrPacketBadFragment         = errors.New("packet bad fragment")
+	ErrPacketTooLarge            = errors.New("packet too large")
+	ErrFragmentLimit             = errors.New("fragment memory limit exceeded")
+	ErrPacketNotEncrypted        = errors.New("packet not encrypted")
+	ErrPacketBadCiphertext       = errors.New("packet bad ciphertext")
+	ErrPacketReplayed            = errors.New("packet replayed")
+	ErrPacketBadPing             = errors.New("packet bad ping")
+	ErrEncryptionRequired        = errors.New("game requires encrypted sessions")
+	ErrNoListenAddresses         = errors.New("no udp listen addresses")
+	ErrPacketBadBundle           = errors.New("packet bad bundle")
+	ErrBadTickRate               = errors.New("bad tick rate")
+	ErrBadRoomMode               = errors.New("bad room mode")
+	ErrPacketBadInput            = errors.New("packet bad input")
+	ErrPacketBadFrame            = errors.New("packet bad frame")
+	ErrFrameLate                 = errors.New("frame already closed")
+	ErrFrameTooFar               = errors.New("frame too far ahead")
+	ErrRoomNotLockstep           = errors.New("room not in lockstep mode")
+	ErrRoomNotRollback           = errors.New("room not in rollback mode")
+	ErrBadInputHistory           = errors.New("bad input history")
+	ErrPacketBadTarget           = errors.New("packet bad target")
+	ErrTargetNotMember           = errors.New("target is not a room member")
+	ErrGroupNotFound             = errors.New("group not found")
+	ErrOwnerNotFound             = errors.New("room has no owner")
+	ErrPacketBadGroup            = errors.New("packet bad group")
+	ErrTooManyGroups             = errors.New("too many groups in room")
+	ErrPacketBadPosition         = errors.New("packet bad position")
+	ErrBadInterest               = errors.New("bad interest settings")
+	ErrRoomFull                  = errors.New("room is full")
+	ErrBadMaxUsers               = errors.New("bad max users")
+	ErrBadReason                 = errors.New("reason too long")
+	ErrPacketBadDisconnect       = errors.New("packet bad disconnect")
+	ErrBadPage                   = errors.New("bad page")
+	ErrBadRoomTTL                = errors.New("bad room ttl")
+	ErrNotInvited                = errors.New("user is not invited to room")
+	ErrBanned                    = errors.New("user is banned from room")
+	ErrPacketBadReject           = errors.New("packet bad reject")
+	ErrBadBanDuration            = errors.New("bad ban duration")
+	ErrRoomLocked                = errors.New("room is locked")
+	ErrNotOwner                  = errors.New("user is not room owner")
+	ErrPacketBadControl          = errors.New("packet bad control")
+	ErrMetadataTooLarge          = errors.New("metadata too large")
+	ErrMetadataForbidden         = errors.New("metadata key not writable")
+	ErrPacketBadMetadata         = errors.New("packet bad metadata")
+	ErrTokenForbidden            = errors.New("token not allowed for room")
+	ErrBackendTokenRequired      = errors.New("backend token required")
 )
@@ -0,0 +1,26 @@
+package protocol
+
+import (
+	"encoding/binary"
+	"github.com/ascenmmo/udp-server/pkg/errors"
+)
+
+// AckPayload builds the payload of an ack packet: the reliable sequence
+// numbers being acknowledged.
+func AckPayload(sequences ...uint32) []byte {
+	buf := make([]byte, 0, len(sequences)*ReliableSize)
+	for _, seq := range sequences {
+		buf = binary.BigEndian.AppendUint32(buf, seq)
+	}
+	return buf
+}
+
+func ParseAck(payload []byte) (sequences []uint32, err error) {
+	if len(payload)%ReliableSize != 0 {
+		return nil, errors.ErrPacketBadAck
+	}
+	for i := 0; i < len(payload); i += ReliableSize {
+		sequences = append(sequences, binary.BigEndian.Uint32(payload[i:i+ReliableSize]))
+	}
+	return sequences, nil
+}
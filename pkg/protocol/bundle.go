@@ -0,0 +1,34 @@
+package protocol
+
+import (
+	"encoding/binary"
+	"github.com/ascenmmo/udp-server/pkg/errors"
+)
+
+const (
+	BundleLengthSize = 2
+	MaxBundleEntry   = 1<<16 - 1
+)
+
+// AppendBundle adds a message to the payload of a bundle packet. Each message
+// is prefixed with its big-endian 2-byte length.
+func AppendBundle(bundle, payload []byte) []byte {
+	bundle = binary.BigEndian.AppendUint16(bundle, uint16(len(payload)))
+	return append(bundle, payload...)
+}
+
+func ParseBundle(payload []byte) (messages [][]byte, err error) {
+	for len(payload) > 0 {
+		if len(payload) < BundleLengthSize {
+			return nil, errors.ErrPacketBadBundle
+		}
+		size := int(binary.BigEndian.Uint16(payload))
+		payload = payload[BundleLengthSize:]
+		if len(payload) < size {
+			return nil, errors.ErrPacketBadBundle
+		}
+		messages = append(messages, payload[:size])
+		payload = payload[size:]
+	}
+	return messages, nil
+}
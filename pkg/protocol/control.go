@@ -0,0 +1,56 @@
+package protocol
+
+import (
+	"github.com/ascenmmo/udp-server/pkg/errors"
+	"github.com/google/uuid"
+)
+
+// Command is the first byte of a control packet. Only the owner of a room
+// may send control packets, except for CommandSetMetadata.
+type Command uint8
+
+const (
+	// CommandStart starts the match. The rest of the payload is relayed
+	// to the other members as is.
+	CommandStart Command = iota + 1
+	// CommandKick removes a member: the 16-byte user ID, then the reason.
+	CommandKick
+	// CommandLock makes the room refuse new members.
+	CommandLock
+	// CommandUnlock lets new members join the room again.
+	CommandUnlock
+	// CommandSetMetadata changes the metadata of the room: metadata
+	// entries, see AppendMetadataEntries. Members may send it too, for the
+	// keys they are allowed to write.
+	CommandSetMetadata
+)
+
+func (c Command) IsValid() bool {
+	return c >= CommandStart && c <= CommandSetMetadata
+}
+
+func ControlPayload(command Command, args []byte) []byte {
+	payload := make([]byte, 0, 1+len(args))
+	payload = append(payload, byte(command))
+	return append(payload, args...)
+}
+
+func ParseControl(payload []byte) (command Command, args []byte, err error) {
+	if len(payload) < 1 || !Command(payload[0]).IsValid() {
+		return 0, nil, errors.ErrPacketBadControl
+	}
+	return Command(payload[0]), payload[1:], nil
+}
+
+func KickArgs(user uuid.UUID, reason string) []byte {
+	args := make([]byte, 0, UserIDSize+len(reason))
+	args = append(args, user[:]...)
+	return append(args, reason...)
+}
+
+func ParseKickArgs(args []byte) (user uuid.UUID, reason string, err error) {
+	if len(args) < UserIDSize || len(args) > UserIDSize+MaxDisconnectText {
+		return user, "", errors.ErrPacketBadControl
+	}
+	return uuid.UUID(args[:UserIDSize]), string(args[UserIDSize:]), nil
+}
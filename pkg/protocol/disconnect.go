@@ -0,0 +1,34 @@
+package protocol
+
+import "github.com/ascenmmo/udp-server/pkg/errors"
+
+type DisconnectReason uint8
+
+const (
+	// DisconnectRoomClosed is sent to the members of a room closed through
+	// the API.
+	DisconnectRoomClosed DisconnectReason = iota + 1
+	// DisconnectKicked is sent to a user kicked from its room.
+	DisconnectKicked
+	// DisconnectBanned is sent to a user banned from its room.
+	DisconnectBanned
+)
+
+// MaxDisconnectText bounds the text of a disconnect packet, so that it always
+// fits in a single datagram.
+const MaxDisconnectText = 255
+
+// DisconnectPayload builds the payload of a disconnect packet: the reason
+// code followed by an optional UTF-8 text.
+func DisconnectPayload(reason DisconnectReason, text string) []byte {
+	payload := make([]byte, 0, 1+len(text))
+	payload = append(payload, byte(reason))
+	return append(payload, text...)
+}
+
+func ParseDisconnect(payload []byte) (reason DisconnectReason, text string, err error) {
+	if len(payload) < 1 || len(payload) > 1+MaxDisconnectText {
+		return 0, "", errors.ErrPacketBadDisconnect
+	}
+	return DisconnectReason(payload[0]), string(payload[1:]), nil
+}
@@ -0,0 +1,42 @@
+package protocol
+
+import "github.com/ascenmmo/udp-server/pkg/errors"
+
+const (
+	MaxFragments = 255
+)
+
+func (p Packet) IsFragment() bool {
+	return p.Header.Flags.Has(FlagFragment)
+}
+
+// Split cuts a packet whose encoded size exceeds mtu into fragments of the
+// same type and flags that fit into mtu. Packets that already fit are
+// returned as is. Clients set FlagSession before splitting so that the MAC
+// added by Sign is accounted for.
+func (p Packet) Split(mtu int, id uint16) ([]Packet, error) {
+	if p.Size() <= mtu {
+		return []Packet{p}, nil
+	}
+
+	p.Header.Flags |= FlagFragment
+	chunk := mtu - p.overhead()
+	if chunk <= 0 {
+		return nil, errors.ErrPacketTooLarge
+	}
+
+	count := (len(p.Payload) + chunk - 1) / chunk
+	if count > MaxFragments {
+		return nil, errors.ErrPacketTooLarge
+	}
+
+	fragments := make([]Packet, 0, count)
+	for i := 0; i < count; i++ {
+		fragment := p
+		fragment.Fragment = Fragment{ID: id, Index: uint8(i), Count: uint8(count)}
+		fragment.Payload = p.Payload[i*chunk : min((i+1)*chunk, len(p.Payload))]
+		fragments = append(fragments, fragment)
+	}
+
+	return fragments, nil
+}
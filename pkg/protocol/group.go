@@ -0,0 +1,44 @@
+package protocol
+
+import (
+	"github.com/ascenmmo/udp-server/pkg/errors"
+	"github.com/google/uuid"
+)
+
+// ParseGroup returns the group name carried by a group join or leave packet.
+// The payload is the name itself.
+func ParseGroup(payload []byte) (name string, err error) {
+	if len(payload) == 0 || len(payload) > MaxGroupName {
+		return "", errors.ErrPacketBadGroup
+	}
+	return string(payload), nil
+}
+
+// GroupEventPayload builds the payload of a group joined or group left
+// packet: the 1-byte length of the group name, the name and the 16-byte IDs
+// of the users that joined or left.
+func GroupEventPayload(name string, users ...uuid.UUID) []byte {
+	payload := make([]byte, 0, 1+len(name)+len(users)*UserIDSize)
+	payload = append(payload, byte(len(name)))
+	payload = append(payload, name...)
+	for _, user := range users {
+		payload = append(payload, user[:]...)
+	}
+	return payload
+}
+
+func ParseGroupEvent(payload []byte) (name string, users []uuid.UUID, err error) {
+	if len(payload) < 1 {
+		return "", nil, errors.ErrPacketBadGroup
+	}
+	size := int(payload[0])
+	payload = payload[1:]
+	if size == 0 || len(payload) < size || (len(payload)-size)%UserIDSize != 0 {
+		return "", nil, errors.ErrPacketBadGroup
+	}
+	name, payload = string(payload[:size]), payload[size:]
+	for ; len(payload) > 0; payload = payload[UserIDSize:] {
+		users = append(users, uuid.UUID(payload[:UserIDSize]))
+	}
+	return name, users, nil
+}
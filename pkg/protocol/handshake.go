@@ -0,0 +1,46 @@
+package protocol
+
+import (
+	"encoding/binary"
+	"github.com/ascenmmo/udp-server/pkg/errors"
+	"github.com/google/uuid"
+)
+
+const (
+	SessionKeySize     = 32
+	HandshakeReplySize = 16 + SessionIDSize + SessionKeySize
+)
+
+// HandshakeReply is the payload of the handshake answer: the user ID, the
+// session ID to put into later packets and the key used to sign them.
+//
+// Encrypted handshakes carry the client's X25519 public key in front of the
+// token. Their reply has a zero Key and ends with the server's public key;
+// both keys of the session are then derived with DeriveSessionKeys.
+type HandshakeReply struct {
+	UserID    uuid.UUID
+	SessionID uint64
+	Key       []byte
+	PublicKey []byte
+}
+
+func (r HandshakeReply) Marshal() []byte {
+	buf := make([]byte, 0, HandshakeReplySize+len(r.PublicKey))
+	buf = append(buf, r.UserID[:]...)
+	buf = binary.BigEndian.AppendUint64(buf, r.SessionID)
+	buf = append(buf, r.Key...)
+	return append(buf, r.PublicKey...)
+}
+
+func UnmarshalHandshakeReply(buf []byte) (reply HandshakeReply, err error) {
+	if len(buf) != HandshakeReplySize && len(buf) != HandshakeReplySize+PublicKeySize {
+		return reply, errors.ErrPacketTooShort
+	}
+	copy(reply.UserID[:], buf[:16])
+	reply.SessionID = binary.BigEndian.Uint64(buf[16 : 16+SessionIDSize])
+	reply.Key = append([]byte(nil), buf[16+SessionIDSize:HandshakeReplySize]...)
+	if len(buf) > HandshakeReplySize {
+		reply.PublicKey = append([]byte(nil), buf[HandshakeReplySize:]...)
+	}
+	return reply, nil
+}
@@ -0,0 +1,103 @@
+package protocol
+
+import (
+	"encoding/binary"
+	"github.com/ascenmmo/udp-server/pkg/errors"
+	"github.com/google/uuid"
+)
+
+const (
+	FrameNumberSize   = 4
+	FrameEntrySize    = 16 + 1 + 2
+	FrameRequestSize  = 2 * FrameNumberSize
+	frameInputMissing = 1
+)
+
+// FrameInput is the input of one member in a lockstep frame. Missing is set
+// when the member did not report its input before the frame deadline.
+type FrameInput struct {
+	UserID  uuid.UUID
+	Input   []byte
+	Missing bool
+}
+
+// InputPayload builds the payload of an input packet: the frame the input
+// was sampled on, then the input.
+func InputPayload(frame uint32, input []byte) []byte {
+	buf := make([]byte, 0, FrameNumberSize+len(input))
+	buf = binary.BigEndian.AppendUint32(buf, frame)
+	return append(buf, input...)
+}
+
+func ParseInput(payload []byte) (frame uint32, input []byte, err error) {
+	if len(payload) < FrameNumberSize {
+		return 0, nil, errors.ErrPacketBadInput
+	}
+	return binary.BigEndian.Uint32(payload), payload[FrameNumberSize:], nil
+}
+
+// FramePayload builds the payload of a frame packet: the frame number, then
+// for every member its user ID, a flags byte (1 when the input is missing),
+// the 2-byte length of the input and the input.
+func FramePayload(frame uint32, inputs []FrameInput) []byte {
+	size := FrameNumberSize
+	for _, in := range inputs {
+		size += FrameEntrySize + len(in.Input)
+	}
+
+	buf := make([]byte, 0, size)
+	buf = binary.BigEndian.AppendUint32(buf, frame)
+	for _, in := range inputs {
+		var flags byte
+		if in.Missing {
+			flags = frameInputMissing
+		}
+		buf = append(buf, in.UserID[:]...)
+		buf = append(buf, flags)
+		buf = binary.BigEndian.AppendUint16(buf, uint16(len(in.Input)))
+		buf = append(buf, in.Input...)
+	}
+	return buf
+}
+
+func ParseFrame(payload []byte) (frame uint32, inputs []FrameInput, err error) {
+	if len(payload) < FrameNumberSize {
+		return 0, nil, errors.ErrPacketBadFrame
+	}
+	frame = binary.BigEndian.Uint32(payload)
+	payload = payload[FrameNumberSize:]
+
+	for len(payload) > 0 {
+		if len(payload) < FrameEntrySize {
+			return 0, nil, errors.ErrPacketBadFrame
+		}
+		var in FrameInput
+		copy(in.UserID[:], payload[:16])
+		in.Missing = payload[16]&frameInputMissing != 0
+		size := int(binary.BigEndian.Uint16(payload[17:FrameEntrySize]))
+		payload = payload[FrameEntrySize:]
+		if len(payload) < size {
+			return 0, nil, errors.ErrPacketBadFrame
+		}
+		in.Input = payload[:size]
+		payload = payload[size:]
+		inputs = append(inputs, in)
+	}
+
+	return frame, inputs, nil
+}
+
+// FrameRequestPayload builds the payload of a frame request: the first and
+// the last frame to send again.
+func FrameRequestPayload(from, to uint32) []byte {
+	buf := make([]byte, 0, FrameRequestSize)
+	buf = binary.BigEndian.AppendUint32(buf, from)
+	return binary.BigEndian.AppendUint32(buf, to)
+}
+
+func ParseFrameRequest(payload []byte) (from, to uint32, err error) {
+	if len(payload) != FrameRequestSize || binary.BigEndian.Uint32(payload) > binary.BigEndian.Uint32(payload[FrameNumberSize:]) {
+		return 0, 0, errors.ErrPacketBadFrame
+	}
+	return binary.BigEndian.Uint32(payload), binary.BigEndian.Uint32(payload[FrameNumberSize:]), nil
+}
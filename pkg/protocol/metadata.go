@@ -0,0 +1,65 @@
+package protocol
+
+import (
+	"encoding/binary"
+	"github.com/ascenmmo/udp-server/pkg/errors"
+	"maps"
+	"slices"
+)
+
+// MetadataSnapshot marks a metadata packet that carries every entry of the
+// room rather than the changed ones.
+const MetadataSnapshot byte = 1
+
+// AppendMetadataEntries appends metadata entries, ordered by key: the 1-byte
+// key length, the key, the 2-byte big-endian value length and the value. An
+// empty value stands for a removed key.
+func AppendMetadataEntries(dst []byte, entries map[string]string) []byte {
+	for _, key := range slices.Sorted(maps.Keys(entries)) {
+		value := entries[key]
+		dst = append(dst, byte(len(key)))
+		dst = append(dst, key...)
+		dst = binary.BigEndian.AppendUint16(dst, uint16(len(value)))
+		dst = append(dst, value...)
+	}
+	return dst
+}
+
+func ParseMetadataEntries(payload []byte) (entries map[string]string, err error) {
+	entries = make(map[string]string)
+	for len(payload) > 0 {
+		size := int(payload[0])
+		if size == 0 || len(payload) < 1+size+2 {
+			return nil, errors.ErrPacketBadMetadata
+		}
+		key := string(payload[1 : 1+size])
+		payload = payload[1+size:]
+
+		size = int(binary.BigEndian.Uint16(payload))
+		if len(payload) < 2+size {
+			return nil, errors.ErrPacketBadMetadata
+		}
+		entries[key] = string(payload[2 : 2+size])
+		payload = payload[2+size:]
+	}
+	return entries, nil
+}
+
+// MetadataPayload builds the payload of a metadata packet: the 8-byte
+// version of the metadata after the change, a flags byte and the entries.
+func MetadataPayload(version uint64, flags byte, entries map[string]string) []byte {
+	payload := binary.BigEndian.AppendUint64(nil, version)
+	payload = append(payload, flags)
+	return AppendMetadataEntries(payload, entries)
+}
+
+func ParseMetadata(payload []byte) (version uint64, flags byte, entries map[string]string, err error) {
+	if len(payload) < 9 {
+		return 0, 0, nil, errors.ErrPacketBadMetadata
+	}
+	entries, err = ParseMetadataEntries(payload[9:])
+	if err != nil {
+		return 0, 0, nil, err
+	}
+	return binary.BigEndian.Uint64(payload), payload[8], entries, nil
+}
@@ -0,0 +1,23 @@
+package protocol
+
+import (
+	"encoding/binary"
+	"github.com/ascenmmo/udp-server/pkg/errors"
+)
+
+const (
+	PingIDSize = 4
+)
+
+// PingPayload builds the payload of a server ping. The client returns it
+// unchanged in its pong.
+func PingPayload(id uint32) []byte {
+	return binary.BigEndian.AppendUint32(make([]byte, 0, PingIDSize), id)
+}
+
+func ParsePing(payload []byte) (id uint32, err error) {
+	if len(payload) != PingIDSize {
+		return 0, errors.ErrPacketBadPing
+	}
+	return binary.BigEndian.Uint32(payload), nil
+}
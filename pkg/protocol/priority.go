@@ -0,0 +1,48 @@
+package protocol
+
+// Priority is the class of a packet, carried in the two bits of the flags
+// selected by PriorityMask. Workers serve the classes by weight and shed the
+// lowest class first when their queue is full.
+type Priority uint8
+
+const (
+	// PriorityNormal is the class of packets that do not set one.
+	PriorityNormal Priority = iota
+	// PriorityLow is for traffic that can be lost, such as voice or
+	// telemetry.
+	PriorityLow
+	// PriorityHigh is for game state.
+	PriorityHigh
+	// PriorityCritical is for messages that must not be delayed by anything
+	// else.
+	PriorityCritical
+
+	Priorities = 4
+)
+
+const (
+	priorityShift       = 8
+	PriorityMask  Flags = 3 << priorityShift
+)
+
+func (p Packet) Priority() Priority {
+	return Priority(p.Header.Flags & PriorityMask >> priorityShift)
+}
+
+func (p *Packet) SetPriority(priority Priority) {
+	p.Header.Flags = p.Header.Flags&^PriorityMask | Flags(priority)<<priorityShift&PriorityMask
+}
+
+// Rank orders the classes from PriorityLow, 0, to PriorityCritical.
+func (p Priority) Rank() int {
+	switch p {
+	case PriorityLow:
+		return 0
+	case PriorityNormal:
+		return 1
+	case PriorityHigh:
+		return 2
+	default:
+		return 3
+	}
+}
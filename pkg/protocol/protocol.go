@@ -0,0 +1,349 @@
+package protocol
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"github.com/ascenmmo/udp-server/pkg/errors"
+	"math"
+)
+
+// Every framed datagram starts with a fixed header:
+//
+//	0      magic
+//	1      protocol version
+//	2      message type
+//	3..4   flags (big endian)
+//	5..8   sequence number (big endian)
+//
+// Optional fields follow the header in the order of their flags. Packets with
+// FlagSession carry the 8-byte session ID right after the header and a MACSize
+// HMAC-SHA256 tag of everything before it at the end of the datagram. Packets
+// with FlagReliable carry the 4-byte sequence number of the reliable channel,
+// packets with FlagFragment carry the fragment group ID, index and count, and
+// handshakes with FlagCookie carry the cookie returned by a retry. Packets
+// with FlagTarget carry the recipients of the packet (see Target), and packets
+// with FlagPosition the sender's position as two big-endian IEEE 754 floats.
+// FlagBypass has no field: it sends a packet past the area-of-interest filter
+// of the room. The payload of packets with FlagEncrypted is sealed with
+// AES-GCM (see Cipher).
+//
+// Datagrams that do not start with Magic are treated as legacy raw-token
+// traffic when the server runs in compatibility mode.
+const (
+	Magic         byte = 0xAE
+	Version       byte = 1
+	HeaderSize         = 9
+	SessionIDSize      = 8
+	ReliableSize       = 4
+	FragmentSize       = 4
+	CookieSize         = 20
+	PositionSize       = 8
+	MACSize            = 16
+)
+
+type MessageType uint8
+
+const (
+	TypeHandshake MessageType = iota + 1
+	TypeData
+	TypePing
+	TypePong
+	TypeLeave
+	TypeAck
+	TypeRetry
+	TypeUserJoined
+	TypeUserLeft
+	TypeBundle
+	TypeInput
+	TypeFrame
+	TypeFrameRequest
+	TypeRollbackInput
+	TypeRollbackInputs
+	TypeGroupJoin
+	TypeGroupLeave
+	TypeGroupJoined
+	TypeGroupLeft
+	TypeDisconnect
+	TypeReject
+	TypeHostChanged
+	TypeControl
+	TypeMetadata
+)
+
+type Flags uint16
+
+const (
+	FlagSession Flags = 1 << iota
+	FlagReliable
+	FlagFragment
+	FlagCookie
+	FlagEncrypted
+	FlagTarget
+	FlagPosition
+	FlagBypass
+)
+
+type Header struct {
+	Version  uint8
+	Type     MessageType
+	Flags    Flags
+	Sequence uint32
+}
+
+// Position is the position of the sender in the plane of the room's
+// area-of-interest grid.
+type Position struct {
+	X, Y float32
+}
+
+func (p Position) IsValid() bool {
+	finite := func(v float32) bool { return !math.IsInf(float64(v), 0) && !math.IsNaN(float64(v)) }
+	return finite(p.X) && finite(p.Y)
+}
+
+type Fragment struct {
+	ID    uint16
+	Index uint8
+	Count uint8
+}
+
+type Packet struct {
+	Header    Header
+	SessionID uint64
+	Reliable  uint32
+	Fragment  Fragment
+	Cookie    []byte
+	Target    Target
+	Position  Position
+	Payload   []byte
+	MAC       []byte
+
+	// Legacy marks a datagram without a header, sent by a raw-token client.
+	Legacy bool
+
+	signed []byte
+}
+
+func (t MessageType) IsValid() bool {
+	return t >= TypeHandshake && t <= TypeMetadata
+}
+
+func (p Packet) IsReliable() bool {
+	return p.Header.Flags.Has(FlagReliable)
+}
+
+func (f Flags) Has(flag Flags) bool {
+	return f&flag == flag
+}
+
+func IsFramed(buf []byte) bool {
+	return len(buf) > 0 && buf[0] == Magic
+}
+
+func NewPacket(msgType MessageType, sequence uint32, payload []byte) Packet {
+	return Packet{
+		Header: Header{
+			Version:  Version,
+			Type:     msgType,
+			Sequence: sequence,
+		},
+		Payload: payload,
+	}
+}
+
+func Decode(buf []byte) (packet Packet, err error) {
+	if len(buf) < HeaderSize {
+		return packet, errors.ErrPacketTooShort
+	}
+	if buf[0] != Magic {
+		return packet, errors.ErrPacketBadMagic
+	}
+
+	header := Header{
+		Version:  buf[1],
+		Type:     MessageType(buf[2]),
+		Flags:    Flags(binary.BigEndian.Uint16(buf[3:5])),
+		Sequence: binary.BigEndian.Uint32(buf[5:9]),
+	}
+	if header.Version != Version {
+		return packet, errors.ErrPacketBadVersion
+	}
+	if !header.Type.IsValid() {
+		return packet, errors.ErrPacketBadType
+	}
+
+	packet.Header = header
+	body := buf[HeaderSize:]
+
+	if header.Flags.Has(FlagSession) {
+		if len(body) < SessionIDSize+MACSize {
+			return packet, errors.ErrPacketTooShort
+		}
+		packet.SessionID = binary.BigEndian.Uint64(body[:SessionIDSize])
+		packet.signed = buf[:len(buf)-MACSize]
+		packet.MAC = buf[len(buf)-MACSize:]
+		body = body[SessionIDSize : len(body)-MACSize]
+	}
+
+	if header.Flags.Has(FlagReliable) {
+		if len(body) < ReliableSize {
+			return packet, errors.ErrPacketTooShort
+		}
+		packet.Reliable = binary.BigEndian.Uint32(body[:ReliableSize])
+		body = body[ReliableSize:]
+	}
+
+	if header.Flags.Has(FlagFragment) {
+		if len(body) < FragmentSize {
+			return packet, errors.ErrPacketTooShort
+		}
+		packet.Fragment = Fragment{
+			ID:    binary.BigEndian.Uint16(body[:2]),
+			Index: body[2],
+			Count: body[3],
+		}
+		if packet.Fragment.Count == 0 || packet.Fragment.Index >= packet.Fragment.Count {
+			return packet, errors.ErrPacketBadFragment
+		}
+		body = body[FragmentSize:]
+	}
+
+	if header.Flags.Has(FlagCookie) {
+		if len(body) < CookieSize {
+			return packet, errors.ErrPacketTooShort
+		}
+		packet.Cookie = body[:CookieSize]
+		body = body[CookieSize:]
+	}
+
+	if header.Flags.Has(FlagTarget) {
+		packet.Target, body, err = parseTarget(body)
+		if err != nil {
+			return packet, err
+		}
+	}
+
+	if header.Flags.Has(FlagPosition) {
+		if len(body) < PositionSize {
+			return packet, errors.ErrPacketTooShort
+		}
+		packet.Position = Position{
+			X: math.Float32frombits(binary.BigEndian.Uint32(body[:4])),
+			Y: math.Float32frombits(binary.BigEndian.Uint32(body[4:8])),
+		}
+		if !packet.Position.IsValid() {
+			return packet, errors.ErrPacketBadPosition
+		}
+		body = body[PositionSize:]
+	}
+
+	packet.Payload = body
+
+	return packet, nil
+}
+
+func (h Header) AppendTo(dst []byte) []byte {
+	dst = append(dst, Magic, h.Version, byte(h.Type))
+	dst = binary.BigEndian.AppendUint16(dst, uint16(h.Flags))
+	dst = binary.BigEndian.AppendUint32(dst, h.Sequence)
+	return dst
+}
+
+// Size returns the length of the encoded packet.
+func (p Packet) Size() int {
+	return p.overhead() + len(p.Payload)
+}
+
+func (p Packet) overhead() int {
+	size := HeaderSize
+	if p.Header.Flags.Has(FlagSession) {
+		size += SessionIDSize + MACSize
+	}
+	if p.Header.Flags.Has(FlagReliable) {
+		size += ReliableSize
+	}
+	if p.Header.Flags.Has(FlagFragment) {
+		size += FragmentSize
+	}
+	if p.Header.Flags.Has(FlagCookie) {
+		size += CookieSize
+	}
+	if p.Header.Flags.Has(FlagTarget) {
+		size += p.Target.size()
+	}
+	if p.Header.Flags.Has(FlagPosition) {
+		size += PositionSize
+	}
+	return size
+}
+
+func (p Packet) Encode() []byte {
+	buf := make([]byte, 0, p.Size())
+	buf = p.appendUnsigned(buf)
+	return append(buf, p.MAC...)
+}
+
+// Sign encodes a session packet and appends its MAC computed with key.
+func (p Packet) Sign(key []byte) []byte {
+	p.Header.Flags |= FlagSession
+	buf := p.appendUnsigned(make([]byte, 0, p.Size()))
+	return append(buf, MAC(key, buf)...)
+}
+
+// Verify checks the MAC of a decoded session packet.
+func (p Packet) Verify(key []byte) bool {
+	if !p.Header.Flags.Has(FlagSession) || len(p.MAC) != MACSize {
+		return false
+	}
+	signed := p.signed
+	if signed == nil {
+		signed = p.appendUnsigned(nil)
+	}
+	return hmac.Equal(p.MAC, MAC(key, signed))
+}
+
+func (p Packet) appendUnsigned(dst []byte) []byte {
+	return append(p.appendHeader(dst), p.Payload...)
+}
+
+// appendHeader appends everything that precedes the payload.
+func (p Packet) appendHeader(dst []byte) []byte {
+	dst = p.Header.AppendTo(dst)
+	if p.Header.Flags.Has(FlagSession) {
+		dst = binary.BigEndian.AppendUint64(dst, p.SessionID)
+	}
+	if p.Header.Flags.Has(FlagReliable) {
+		dst = binary.BigEndian.AppendUint32(dst, p.Reliable)
+	}
+	if p.Header.Flags.Has(FlagFragment) {
+		dst = binary.BigEndian.AppendUint16(dst, p.Fragment.ID)
+		dst = append(dst, p.Fragment.Index, p.Fragment.Count)
+	}
+	if p.Header.Flags.Has(FlagCookie) {
+		dst = append(dst, p.Cookie...)
+	}
+	if p.Header.Flags.Has(FlagTarget) {
+		dst = p.Target.appendTo(dst)
+	}
+	if p.Header.Flags.Has(FlagPosition) {
+		dst = binary.BigEndian.AppendUint32(dst, math.Float32bits(p.Position.X))
+		dst = binary.BigEndian.AppendUint32(dst, math.Float32bits(p.Position.Y))
+	}
+	return dst
+}
+
+func MAC(key, data []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(data)
+	return mac.Sum(nil)[:MACSize]
+}
+
+// Marshal returns the bytes sent on the wire: the bare payload for legacy
+// clients, the framed packet otherwise.
+func (p Packet) Marshal(legacy bool) []byte {
+	if legacy {
+		return p.Payload
+	}
+	return p.Encode()
+}
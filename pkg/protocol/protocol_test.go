@@ -0,0 +1,409 @@
+package protocol
+
+import (
+	"bytes"
+	"encoding/hex"
+	"github.com/ascenmmo/udp-server/pkg/errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"math"
+	"testing"
+)
+
+func TestEncodeDecode(t *testing.T) {
+	packet := NewPacket(TypeData, 42, []byte("payload"))
+	packet.Header.Flags = 0x8000
+
+	buf := packet.Encode()
+	assert.Equal(t, HeaderSize+len("payload"), len(buf))
+	assert.True(t, IsFramed(buf))
+
+	decoded, err := Decode(buf)
+	assert.NoError(t, err)
+	assert.Equal(t, packet.Header, decoded.Header)
+	assert.Equal(t, []byte("payload"), decoded.Payload)
+}
+
+func TestDecodeErrors(t *testing.T) {
+	_, err := Decode([]byte{Magic, Version})
+	assert.Equal(t, errors.ErrPacketTooShort, err)
+
+	buf := NewPacket(TypeData, 1, nil).Encode()
+	buf[0] = 'e'
+	_, err = Decode(buf)
+	assert.Equal(t, errors.ErrPacketBadMagic, err)
+
+	buf = NewPacket(TypeData, 1, nil).Encode()
+	buf[1] = Version + 1
+	_, err = Decode(buf)
+	assert.Equal(t, errors.ErrPacketBadVersion, err)
+
+	buf = NewPacket(MessageType(0xff), 1, nil).Encode()
+	_, err = Decode(buf)
+	assert.Equal(t, errors.ErrPacketBadType, err)
+}
+
+func TestMarshalLegacy(t *testing.T) {
+	packet := NewPacket(TypeData, 7, make([]byte, 343))
+
+	assert.Equal(t, packet.Payload, packet.Marshal(true))
+	assert.Equal(t, packet.Encode(), packet.Marshal(false))
+}
+
+func TestSignVerify(t *testing.T) {
+	key := []byte("0123456789abcdef0123456789abcdef")
+	packet := NewPacket(TypeData, 3, []byte("payload"))
+	packet.SessionID = 0xdeadbeef
+
+	buf := packet.Sign(key)
+	decoded, err := Decode(buf)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0xdeadbeef), decoded.SessionID)
+	assert.Equal(t, []byte("payload"), decoded.Payload)
+	assert.True(t, decoded.Verify(key))
+	assert.False(t, decoded.Verify([]byte("another key")))
+
+	buf[HeaderSize+SessionIDSize] ^= 0xff
+	tampered, err := Decode(buf)
+	assert.NoError(t, err)
+	assert.False(t, tampered.Verify(key))
+}
+
+func TestHandshakeReply(t *testing.T) {
+	reply := HandshakeReply{
+		UserID:    uuid.New(),
+		SessionID: 42,
+		Key:       make([]byte, SessionKeySize),
+	}
+
+	decoded, err := UnmarshalHandshakeReply(reply.Marshal())
+	assert.NoError(t, err)
+	assert.Equal(t, reply, decoded)
+}
+
+func TestReliableAck(t *testing.T) {
+	packet := NewPacket(TypeData, 1, []byte("event"))
+	packet.Header.Flags |= FlagReliable
+	packet.Reliable = 9
+	packet.SessionID = 5
+
+	decoded, err := Decode(packet.Sign([]byte("key")))
+	assert.NoError(t, err)
+	assert.True(t, decoded.IsReliable())
+	assert.Equal(t, uint32(9), decoded.Reliable)
+	assert.Equal(t, []byte("event"), decoded.Payload)
+	assert.True(t, decoded.Verify([]byte("key")))
+
+	sequences, err := ParseAck(AckPayload(1, 2, 3))
+	assert.NoError(t, err)
+	assert.Equal(t, []uint32{1, 2, 3}, sequences)
+
+	_, err = ParseAck([]byte{1, 2})
+	assert.Equal(t, errors.ErrPacketBadAck, err)
+}
+
+func TestSplit(t *testing.T) {
+	payload := make([]byte, 5000)
+	for i := range payload {
+		payload[i] = byte(i)
+	}
+	packet := NewPacket(TypeData, 1, payload)
+
+	fragments, err := packet.Split(1200, 7)
+	assert.NoError(t, err)
+	assert.Len(t, fragments, 5)
+
+	var joined []byte
+	for i, fragment := range fragments {
+		buf := fragment.Encode()
+		assert.LessOrEqual(t, len(buf), 1200)
+
+		decoded, err := Decode(buf)
+		assert.NoError(t, err)
+		assert.True(t, decoded.IsFragment())
+		assert.Equal(t, Fragment{ID: 7, Index: uint8(i), Count: 5}, decoded.Fragment)
+		joined = append(joined, decoded.Payload...)
+	}
+	assert.Equal(t, payload, joined)
+
+	small, err := NewPacket(TypeData, 1, []byte("small")).Split(1200, 8)
+	assert.NoError(t, err)
+	assert.Len(t, small, 1)
+	assert.False(t, small[0].IsFragment())
+
+	_, err = NewPacket(TypeData, 1, make([]byte, 1<<20)).Split(1200, 9)
+	assert.Equal(t, errors.ErrPacketTooLarge, err)
+}
+
+func TestCookie(t *testing.T) {
+	packet := NewPacket(TypeHandshake, 1, []byte("token"))
+	packet.Header.Flags |= FlagCookie
+	packet.Cookie = make([]byte, CookieSize)
+	packet.Cookie[0] = 1
+
+	buf := packet.Encode()
+	assert.Equal(t, packet.Size(), len(buf))
+
+	decoded, err := Decode(buf)
+	assert.NoError(t, err)
+	assert.Equal(t, packet.Cookie, decoded.Cookie)
+	assert.Equal(t, []byte("token"), decoded.Payload)
+}
+
+func TestCipher(t *testing.T) {
+	key, _ := DeriveSessionKeys(bytes.Repeat([]byte{7}, 32), 1)
+	cipher, err := NewCipher(key)
+	assert.NoError(t, err)
+
+	packet := NewPacket(TypeData, 5, []byte("secret"))
+	sealed := cipher.Seal(packet, ClientToServer)
+	assert.True(t, sealed.Header.Flags.Has(FlagEncrypted))
+	assert.Len(t, sealed.Payload, len("secret")+TagSize)
+
+	decoded, err := Decode(sealed.Encode())
+	assert.NoError(t, err)
+
+	opened, err := cipher.Open(decoded, ClientToServer)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("secret"), opened.Payload)
+
+	_, err = cipher.Open(decoded, ServerToClient)
+	assert.ErrorIs(t, err, errors.ErrPacketBadCiphertext)
+
+	decoded.Header.Sequence++
+	_, err = cipher.Open(decoded, ClientToServer)
+	assert.ErrorIs(t, err, errors.ErrPacketBadCiphertext)
+
+	_, err = cipher.Open(packet, ClientToServer)
+	assert.ErrorIs(t, err, errors.ErrPacketNotEncrypted)
+}
+
+func TestHKDF(t *testing.T) {
+	// RFC 5869, test case 1, first 32 bytes of the output.
+	secret := bytes.Repeat([]byte{0x0b}, 22)
+	salt := []byte{0x00, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0a, 0x0b, 0x0c}
+	info := string([]byte{0xf0, 0xf1, 0xf2, 0xf3, 0xf4, 0xf5, 0xf6, 0xf7, 0xf8, 0xf9})
+	want, err := hex.DecodeString("3cb25f25faacd57a90434f64d0362f2a2d2d0a90cf1a5a4c5db02d56ecc4c5bf")
+	assert.NoError(t, err)
+	assert.Equal(t, want, hkdf(secret, salt, info))
+}
+
+func TestEncryptedHandshakeReply(t *testing.T) {
+	reply := HandshakeReply{
+		UserID:    uuid.New(),
+		SessionID: 9,
+		Key:       make([]byte, SessionKeySize),
+		PublicKey: bytes.Repeat([]byte{3}, PublicKeySize),
+	}
+
+	parsed, err := UnmarshalHandshakeReply(reply.Marshal())
+	assert.NoError(t, err)
+	assert.Equal(t, reply, parsed)
+}
+
+func TestPingPayload(t *testing.T) {
+	id, err := ParsePing(PingPayload(77))
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(77), id)
+
+	_, err = ParsePing([]byte{1})
+	assert.ErrorIs(t, err, errors.ErrPacketBadPing)
+}
+
+func TestBundle(t *testing.T) {
+	var bundle []byte
+	bundle = AppendBundle(bundle, []byte("first"))
+	bundle = AppendBundle(bundle, nil)
+	bundle = AppendBundle(bundle, []byte("third"))
+
+	messages, err := ParseBundle(bundle)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]byte{[]byte("first"), {}, []byte("third")}, messages)
+
+	_, err = ParseBundle(bundle[:len(bundle)-1])
+	assert.ErrorIs(t, err, errors.ErrPacketBadBundle)
+	_, err = ParseBundle([]byte{0})
+	assert.ErrorIs(t, err, errors.ErrPacketBadBundle)
+}
+
+func TestLockstepPayloads(t *testing.T) {
+	frame, input, err := ParseInput(InputPayload(42, []byte("move")))
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(42), frame)
+	assert.Equal(t, []byte("move"), input)
+
+	inputs := []FrameInput{
+		{UserID: uuid.New(), Input: []byte("move")},
+		{UserID: uuid.New(), Input: []byte{}, Missing: true},
+	}
+	frame, parsed, err := ParseFrame(FramePayload(7, inputs))
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(7), frame)
+	assert.Equal(t, inputs, parsed)
+
+	payload := FramePayload(7, inputs)
+	_, _, err = ParseFrame(payload[:len(payload)-1])
+	assert.ErrorIs(t, err, errors.ErrPacketBadFrame)
+
+	from, to, err := ParseFrameRequest(FrameRequestPayload(3, 9))
+	assert.NoError(t, err)
+	assert.Equal(t, []uint32{3, 9}, []uint32{from, to})
+	_, _, err = ParseFrameRequest(FrameRequestPayload(9, 3))
+	assert.ErrorIs(t, err, errors.ErrPacketBadFrame)
+}
+
+func TestRollbackPayloads(t *testing.T) {
+	confirmed, first, inputs, err := ParseRollbackInput(RollbackInputPayload(9, 10, [][]byte{[]byte("a"), {}, []byte("c")}))
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(9), confirmed)
+	assert.Equal(t, uint32(10), first)
+	assert.Equal(t, [][]byte{[]byte("a"), {}, []byte("c")}, inputs)
+
+	payload := RollbackInputPayload(9, 10, [][]byte{[]byte("a")})
+	_, _, _, err = ParseRollbackInput(payload[:len(payload)-1])
+	assert.ErrorIs(t, err, errors.ErrPacketBadInput)
+	_, _, _, err = ParseRollbackInput(append(payload, 0))
+	assert.ErrorIs(t, err, errors.ErrPacketBadInput)
+
+	players := []PlayerInputs{
+		{UserID: uuid.New(), First: 4, Inputs: [][]byte{[]byte("x"), []byte("y")}},
+		{UserID: uuid.New(), First: 6, Inputs: [][]byte{}},
+	}
+	parsed, err := ParseRollbackInputs(RollbackInputsPayload(players))
+	assert.NoError(t, err)
+	assert.Equal(t, players, parsed)
+}
+
+func TestTargetEncodeDecode(t *testing.T) {
+	for _, target := range []Target{
+		{Kind: TargetAll},
+		{Kind: TargetUsers, Users: []uuid.UUID{uuid.New(), uuid.New()}},
+		{Kind: TargetGroup, Group: "red"},
+		{Kind: TargetOwner},
+	} {
+		packet := NewPacket(TypeData, 5, []byte("payload"))
+		packet.Header.Flags |= FlagReliable | FlagTarget
+		packet.Reliable = 9
+		packet.Target = target
+
+		buf := packet.Encode()
+		assert.Equal(t, packet.Size(), len(buf))
+
+		decoded, err := Decode(buf)
+		assert.NoError(t, err)
+		assert.Equal(t, target, decoded.Target)
+		assert.Equal(t, uint32(9), decoded.Reliable)
+		assert.Equal(t, []byte("payload"), decoded.Payload)
+	}
+
+	packet := NewPacket(TypeData, 5, nil)
+	packet.Header.Flags |= FlagTarget
+	packet.Target = Target{Kind: TargetOwner + 1}
+	_, err := Decode(packet.Encode())
+	assert.Equal(t, errors.ErrPacketBadTarget, err)
+
+	packet.Target = Target{Kind: TargetUsers, Users: []uuid.UUID{uuid.New()}}
+	buf := packet.Encode()
+	_, err = Decode(buf[:len(buf)-1])
+	assert.Equal(t, errors.ErrPacketTooShort, err)
+}
+
+func TestGroupEvent(t *testing.T) {
+	users := []uuid.UUID{uuid.New(), uuid.New()}
+
+	name, parsed, err := ParseGroupEvent(GroupEventPayload("red", users...))
+	assert.NoError(t, err)
+	assert.Equal(t, "red", name)
+	assert.Equal(t, users, parsed)
+
+	_, _, err = ParseGroupEvent(GroupEventPayload("red", users...)[:10])
+	assert.Equal(t, errors.ErrPacketBadGroup, err)
+	_, err = ParseGroup(nil)
+	assert.Equal(t, errors.ErrPacketBadGroup, err)
+}
+
+func TestPositionEncodeDecode(t *testing.T) {
+	packet := NewPacket(TypeData, 5, []byte("payload"))
+	packet.Header.Flags |= FlagTarget | FlagPosition | FlagBypass
+	packet.Target = Target{Kind: TargetAll}
+	packet.Position = Position{X: 1.5, Y: -20}
+
+	decoded, err := Decode(packet.Encode())
+	assert.NoError(t, err)
+	assert.Equal(t, packet.Position, decoded.Position)
+	assert.Equal(t, packet.Target, decoded.Target)
+	assert.Equal(t, []byte("payload"), decoded.Payload)
+
+	packet.Position.X = float32(math.Inf(1))
+	_, err = Decode(packet.Encode())
+	assert.Equal(t, errors.ErrPacketBadPosition, err)
+}
+
+func TestPriority(t *testing.T) {
+	packet := NewPacket(TypeData, 1, nil)
+	packet.Header.Flags |= FlagReliable
+	assert.Equal(t, PriorityNormal, packet.Priority())
+
+	packet.SetPriority(PriorityCritical)
+	decoded, err := Decode(packet.Encode())
+	assert.NoError(t, err)
+	assert.Equal(t, PriorityCritical, decoded.Priority())
+	assert.True(t, decoded.IsReliable())
+
+	packet.SetPriority(PriorityLow)
+	assert.Equal(t, PriorityLow, packet.Priority())
+	assert.Less(t, PriorityLow.Rank(), PriorityNormal.Rank())
+}
+
+func TestDisconnectPayload(t *testing.T) {
+	reason, text, err := ParseDisconnect(DisconnectPayload(DisconnectRoomClosed, "bye"))
+	assert.NoError(t, err)
+	assert.Equal(t, DisconnectRoomClosed, reason)
+	assert.Equal(t, "bye", text)
+
+	_, _, err = ParseDisconnect(nil)
+	assert.Equal(t, errors.ErrPacketBadDisconnect, err)
+}
+
+func TestRejectPayload(t *testing.T) {
+	code, text, err := ParseReject(RejectPayload(errors.ErrRoomFull))
+	assert.NoError(t, err)
+	assert.Equal(t, errors.CodeRoomFull, code)
+	assert.Equal(t, errors.ErrRoomFull.Error(), text)
+
+	code, _, err = ParseReject(RejectPayload(errors.ErrPacketTooShort))
+	assert.NoError(t, err)
+	assert.Equal(t, errors.CodeUnknown, code)
+}
+
+func TestControlPayload(t *testing.T) {
+	user := uuid.New()
+	command, args, err := ParseControl(ControlPayload(CommandKick, KickArgs(user, "afk")))
+	assert.NoError(t, err)
+	assert.Equal(t, CommandKick, command)
+	kicked, reason, err := ParseKickArgs(args)
+	assert.NoError(t, err)
+	assert.Equal(t, user, kicked)
+	assert.Equal(t, "afk", reason)
+
+	_, _, err = ParseControl([]byte{0})
+	assert.Equal(t, errors.ErrPacketBadControl, err)
+	_, _, err = ParseKickArgs(user[:8])
+	assert.Equal(t, errors.ErrPacketBadControl, err)
+}
+
+func TestMetadataPayload(t *testing.T) {
+	entries := map[string]string{"mode": "ctf", "map": "dust", "gone": ""}
+	version, flags, parsed, err := ParseMetadata(MetadataPayload(7, MetadataSnapshot, entries))
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(7), version)
+	assert.Equal(t, MetadataSnapshot, flags)
+	assert.Equal(t, entries, parsed)
+
+	payload := AppendMetadataEntries(nil, map[string]string{"b": "2", "a": "1"})
+	assert.Equal(t, []byte{1, 'a', 0, 1, '1', 1, 'b', 0, 1, '2'}, payload, "entries are ordered by key")
+	_, err = ParseMetadataEntries(payload[:len(payload)-1])
+	assert.Equal(t, errors.ErrPacketBadMetadata, err)
+	_, _, _, err = ParseMetadata([]byte{0, 0, 0})
+	assert.Equal(t, errors.ErrPacketBadMetadata, err)
+}
@@ -0,0 +1,19 @@
+package protocol
+
+import "github.com/ascenmmo/udp-server/pkg/errors"
+
+// RejectPayload builds the payload of a reject packet, the answer to a
+// handshake that was refused: the error code followed by the error text.
+func RejectPayload(err error) []byte {
+	text := err.Error()
+	payload := make([]byte, 0, 1+len(text))
+	payload = append(payload, byte(errors.CodeOf(err)))
+	return append(payload, text...)
+}
+
+func ParseReject(payload []byte) (code errors.Code, text string, err error) {
+	if len(payload) < 1 {
+		return 0, "", errors.ErrPacketBadReject
+	}
+	return errors.Code(payload[0]), string(payload[1:]), nil
+}
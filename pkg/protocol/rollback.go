@@ -0,0 +1,122 @@
+package protocol
+
+import (
+	"encoding/binary"
+	"github.com/ascenmmo/udp-server/pkg/errors"
+	"github.com/google/uuid"
+)
+
+const (
+	RollbackInputHeaderSize = 2*FrameNumberSize + 2
+	PlayerInputsHeaderSize  = 16 + FrameNumberSize + 2
+	inputLengthSize         = 2
+)
+
+// PlayerInputs are the inputs of one player for consecutive frames, starting
+// with First.
+type PlayerInputs struct {
+	UserID uuid.UUID
+	First  uint32
+	Inputs [][]byte
+}
+
+// RollbackInputPayload builds the payload of a rollback input packet: the
+// last frame for which the client has every other player's input, the first
+// frame of the inputs, their count and the inputs, each prefixed with its
+// 2-byte length.
+func RollbackInputPayload(confirmed, first uint32, inputs [][]byte) []byte {
+	buf := make([]byte, 0, 2*FrameNumberSize+inputsSize(inputs))
+	buf = binary.BigEndian.AppendUint32(buf, confirmed)
+	buf = binary.BigEndian.AppendUint32(buf, first)
+	return appendInputs(buf, inputs)
+}
+
+func ParseRollbackInput(payload []byte) (confirmed, first uint32, inputs [][]byte, err error) {
+	if len(payload) < RollbackInputHeaderSize {
+		return 0, 0, nil, errors.ErrPacketBadInput
+	}
+	confirmed = binary.BigEndian.Uint32(payload)
+	first = binary.BigEndian.Uint32(payload[FrameNumberSize:])
+
+	inputs, rest, err := parseInputs(payload[2*FrameNumberSize:])
+	if err != nil || len(rest) > 0 {
+		return 0, 0, nil, errors.ErrPacketBadInput
+	}
+	return confirmed, first, inputs, nil
+}
+
+// RollbackInputsPayload builds the payload of the inputs the server sends to
+// a rollback client: for every player its user ID, the first frame, the
+// count and the inputs, each prefixed with its 2-byte length.
+func RollbackInputsPayload(players []PlayerInputs) []byte {
+	size := 0
+	for _, p := range players {
+		size += 16 + FrameNumberSize + inputsSize(p.Inputs)
+	}
+
+	buf := make([]byte, 0, size)
+	for _, p := range players {
+		buf = append(buf, p.UserID[:]...)
+		buf = binary.BigEndian.AppendUint32(buf, p.First)
+		buf = appendInputs(buf, p.Inputs)
+	}
+	return buf
+}
+
+func ParseRollbackInputs(payload []byte) (players []PlayerInputs, err error) {
+	for len(payload) > 0 {
+		if len(payload) < PlayerInputsHeaderSize {
+			return nil, errors.ErrPacketBadInput
+		}
+		var p PlayerInputs
+		copy(p.UserID[:], payload[:16])
+		p.First = binary.BigEndian.Uint32(payload[16:])
+
+		p.Inputs, payload, err = parseInputs(payload[16+FrameNumberSize:])
+		if err != nil {
+			return nil, err
+		}
+		players = append(players, p)
+	}
+	return players, nil
+}
+
+func inputsSize(inputs [][]byte) int {
+	size := 2
+	for _, input := range inputs {
+		size += inputLengthSize + len(input)
+	}
+	return size
+}
+
+func appendInputs(buf []byte, inputs [][]byte) []byte {
+	buf = binary.BigEndian.AppendUint16(buf, uint16(len(inputs)))
+	for _, input := range inputs {
+		buf = binary.BigEndian.AppendUint16(buf, uint16(len(input)))
+		buf = append(buf, input...)
+	}
+	return buf
+}
+
+func parseInputs(payload []byte) (inputs [][]byte, rest []byte, err error) {
+	if len(payload) < 2 {
+		return nil, nil, errors.ErrPacketBadInput
+	}
+	count := int(binary.BigEndian.Uint16(payload))
+	payload = payload[2:]
+
+	inputs = make([][]byte, 0, count)
+	for i := 0; i < count; i++ {
+		if len(payload) < inputLengthSize {
+			return nil, nil, errors.ErrPacketBadInput
+		}
+		size := int(binary.BigEndian.Uint16(payload))
+		payload = payload[inputLengthSize:]
+		if len(payload) < size {
+			return nil, nil, errors.ErrPacketBadInput
+		}
+		inputs = append(inputs, payload[:size])
+		payload = payload[size:]
+	}
+	return inputs, payload, nil
+}
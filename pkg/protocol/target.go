@@ -0,0 +1,114 @@
+package protocol
+
+import (
+	"github.com/ascenmmo/udp-server/pkg/errors"
+	"github.com/google/uuid"
+)
+
+type TargetKind uint8
+
+const (
+	// TargetOthers sends a packet to every member of the room but the
+	// sender. Packets without FlagTarget use it.
+	TargetOthers TargetKind = iota
+	// TargetAll sends a packet to every member of the room, the sender
+	// included.
+	TargetAll
+	// TargetUsers sends a packet to the listed members of the room.
+	TargetUsers
+	// TargetGroup sends a packet to the members of a named group of the room.
+	TargetGroup
+	// TargetOwner sends a packet to the owner of the room.
+	TargetOwner
+)
+
+const (
+	UserIDSize     = 16
+	MaxTargetUsers = 255
+	MaxGroupName   = 255
+)
+
+// Target is the addressing field of packets with FlagTarget: the kind byte,
+// followed by a 1-byte count and the 16-byte user IDs for TargetUsers, or by
+// a 1-byte length and the name for TargetGroup.
+type Target struct {
+	Kind  TargetKind
+	Users []uuid.UUID
+	Group string
+}
+
+func (k TargetKind) IsValid() bool {
+	return k <= TargetOwner
+}
+
+func (t Target) size() int {
+	switch t.Kind {
+	case TargetUsers:
+		return 2 + len(t.Users)*UserIDSize
+	case TargetGroup:
+		return 2 + len(t.Group)
+	default:
+		return 1
+	}
+}
+
+func (t Target) appendTo(dst []byte) []byte {
+	dst = append(dst, byte(t.Kind))
+	switch t.Kind {
+	case TargetUsers:
+		dst = append(dst, byte(len(t.Users)))
+		for _, user := range t.Users {
+			dst = append(dst, user[:]...)
+		}
+	case TargetGroup:
+		dst = append(dst, byte(len(t.Group)))
+		dst = append(dst, t.Group...)
+	}
+	return dst
+}
+
+// parseTarget reads the target at the start of body and returns the rest.
+func parseTarget(body []byte) (target Target, rest []byte, err error) {
+	if len(body) < 1 {
+		return target, nil, errors.ErrPacketTooShort
+	}
+	target.Kind, body = TargetKind(body[0]), body[1:]
+	if !target.Kind.IsValid() {
+		return target, nil, errors.ErrPacketBadTarget
+	}
+
+	switch target.Kind {
+	case TargetUsers:
+		if len(body) < 1 {
+			return target, nil, errors.ErrPacketTooShort
+		}
+		count := int(body[0])
+		body = body[1:]
+		if count == 0 {
+			return target, nil, errors.ErrPacketBadTarget
+		}
+		if len(body) < count*UserIDSize {
+			return target, nil, errors.ErrPacketTooShort
+		}
+		target.Users = make([]uuid.UUID, count)
+		for i := range target.Users {
+			copy(target.Users[i][:], body[:UserIDSize])
+			body = body[UserIDSize:]
+		}
+	case TargetGroup:
+		if len(body) < 1 {
+			return target, nil, errors.ErrPacketTooShort
+		}
+		size := int(body[0])
+		body = body[1:]
+		if size == 0 {
+			return target, nil, errors.ErrPacketBadTarget
+		}
+		if len(body) < size {
+			return target, nil, errors.ErrPacketTooShort
+		}
+		target.Group, body = string(body[:size]), body[size:]
+	}
+
+	return target, body, nil
+}
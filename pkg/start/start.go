@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	tokengenerator "github.com/ascenmmo/token-generator/token_generator"
+	"github.com/ascenmmo/udp-server/env"
+	"github.com/ascenmmo/udp-server/internal/cookie"
 	"github.com/ascenmmo/udp-server/internal/handler/tcp"
 	"github.com/ascenmmo/udp-server/internal/handler/udp"
 	"github.com/ascenmmo/udp-server/internal/service"
@@ -24,11 +26,22 @@ func StartUDP(ctx context.Context, address string, tcpPort, udpPort string, toke
 		return err
 	}
 
-	newService := service.NewService(tokenGen, ramDB, logger)
+	cookieGen, err := cookie.NewGenerator()
+	if err != nil {
+		return err
+	}
+
+	newService := service.NewService(tokenGen, cookieGen, ramDB, service.Config{
+		MTU:               env.MTU,
+		MaxFragmentedSize: env.MaxFragmentedSize,
+		IdleTimeout:       env.IdleTimeout,
+		LegacyIdleTimeout: env.LegacyIdleTimeout,
+		PingInterval:      env.PingInterval,
+	}, logger)
 
 	errors := make(chan error)
 
-	newUDP, err := udp.NewWorkerUDP(udpPort, newService, udpRateLimit, rateLimitDB, logger)
+	newUDP, err := udp.NewWorkerUDP(listenAddresses(udpPort), env.UDPSockets, newService, udpRateLimit, rateLimitDB, env.LegacyProtocol, logger)
 	if err != nil {
 		return err
 	}
@@ -48,7 +61,7 @@ func StartUDP(ctx context.Context, address string, tcpPort, udpPort string, toke
 	}
 
 	go func() {
-		serverSettings := tcp.NewServerSettings(utils.NewRateLimit(10, rateLimitDB), newService)
+		serverSettings := tcp.NewServerSettings(utils.NewRateLimit(10, rateLimitDB), newService, tcpPort, udpPort)
 
 		services := []transport.Option{
 			transport.MaxBodySize(10 * 1024 * 1024),
@@ -68,6 +81,15 @@ func StartUDP(ctx context.Context, address string, tcpPort, udpPort string, toke
 	return err
 }
 
+// listenAddresses returns the configured UDP listen addresses, or udpPort on
+// every interface.
+func listenAddresses(udpPort string) []string {
+	if len(env.UDPListenAddresses) > 0 {
+		return env.UDPListenAddresses
+	}
+	return []string{":" + udpPort}
+}
+
 func logMemoryUsage(logger zerolog.Logger) {
 	ticker := time.NewTicker(time.Second * 10)
 	go func() {
@@ -49,4 +49,20 @@ func (http *httpServerSettings) SetRoutes(route *fiber.App) {
 	route.Post("/api/v1/udp/serverSettings/getServerSettings", http.serveGetServerSettings)
 	route.Post("/api/v1/udp/serverSettings/createRoom", http.serveCreateRoom)
 	route.Post("/api/v1/udp/serverSettings/getDeletedRooms", http.serveGetDeletedRooms)
+	route.Post("/api/v1/udp/serverSettings/getRoomStats", http.serveGetRoomStats)
+	route.Post("/api/v1/udp/serverSettings/getLinkQuality", http.serveGetLinkQuality)
+	route.Post("/api/v1/udp/serverSettings/setGameSettings", http.serveSetGameSettings)
+	route.Post("/api/v1/udp/serverSettings/getGameSettings", http.serveGetGameSettings)
+	route.Post("/api/v1/udp/serverSettings/joinGroup", http.serveJoinGroup)
+	route.Post("/api/v1/udp/serverSettings/leaveGroup", http.serveLeaveGroup)
+	route.Post("/api/v1/udp/serverSettings/getGroups", http.serveGetGroups)
+	route.Post("/api/v1/udp/serverSettings/listRooms", http.serveListRooms)
+	route.Post("/api/v1/udp/serverSettings/getRoom", http.serveGetRoom)
+	route.Post("/api/v1/udp/serverSettings/closeRoom", http.serveCloseRoom)
+	route.Post("/api/v1/udp/serverSettings/updateRoom", http.serveUpdateRoom)
+	route.Post("/api/v1/udp/serverSettings/kickUser", http.serveKickUser)
+	route.Post("/api/v1/udp/serverSettings/banUser", http.serveBanUser)
+	route.Post("/api/v1/udp/serverSettings/getRoomBans", http.serveGetRoomBans)
+	route.Post("/api/v1/udp/serverSettings/getGameBans", http.serveGetGameBans)
+	route.Post("/api/v1/udp/serverSettings/setRoomMetadata", http.serveSetRoomMetadata)
 }
@@ -124,3 +124,340 @@ func (m loggerServerSettings) GetDeletedRooms(ctx context.Context, token string,
 	}(time.Now())
 	return m.next.GetDeletedRooms(ctx, token, ids)
 }
+
+func (m loggerServerSettings) GetRoomStats(ctx context.Context, token string) (stats types.RoomStats, err error) {
+	logger := log.Ctx(ctx).With().Str("service", "ServerSettings").Str("method", "getRoomStats").Logger()
+	defer func(begin time.Time) {
+		logHandle := func(ev *zerolog.Event) {
+			fields := map[string]interface{}{
+				"request":  viewer.Sprintf("%+v", requestServerSettingsGetRoomStats{Token: token}),
+				"response": viewer.Sprintf("%+v", responseServerSettingsGetRoomStats{Stats: stats}),
+			}
+			ev.Fields(fields).Str("took", time.Since(begin).String())
+		}
+		if err != nil {
+			logger.Error().Err(err).Func(logHandle).Msg("call getRoomStats")
+			return
+		}
+		logger.Info().Func(logHandle).Msg("call getRoomStats")
+	}(time.Now())
+	return m.next.GetRoomStats(ctx, token)
+}
+
+func (m loggerServerSettings) GetLinkQuality(ctx context.Context, token string) (quality []types.LinkQuality, err error) {
+	logger := log.Ctx(ctx).With().Str("service", "ServerSettings").Str("method", "getLinkQuality").Logger()
+	defer func(begin time.Time) {
+		logHandle := func(ev *zerolog.Event) {
+			fields := map[string]interface{}{
+				"request":  viewer.Sprintf("%+v", requestServerSettingsGetLinkQuality{Token: token}),
+				"response": viewer.Sprintf("%+v", responseServerSettingsGetLinkQuality{Quality: quality}),
+			}
+			ev.Fields(fields).Str("took", time.Since(begin).String())
+		}
+		if err != nil {
+			logger.Error().Err(err).Func(logHandle).Msg("call getLinkQuality")
+			return
+		}
+		logger.Info().Func(logHandle).Msg("call getLinkQuality")
+	}(time.Now())
+	return m.next.GetLinkQuality(ctx, token)
+}
+
+func (m loggerServerSettings) SetGameSettings(ctx context.Context, token string, settings types.GameSettings) (err error) {
+	logger := log.Ctx(ctx).With().Str("service", "ServerSettings").Str("method", "setGameSettings").Logger()
+	defer func(begin time.Time) {
+		logHandle := func(ev *zerolog.Event) {
+			fields := map[string]interface{}{
+				"request": viewer.Sprintf("%+v", requestServerSettingsSetGameSettings{
+					Settings: settings,
+					Token:    token,
+				}),
+				"response": viewer.Sprintf("%+v", responseServerSettingsSetGameSettings{}),
+			}
+			ev.Fields(fields).Str("took", time.Since(begin).String())
+		}
+		if err != nil {
+			logger.Error().Err(err).Func(logHandle).Msg("call setGameSettings")
+			return
+		}
+		logger.Info().Func(logHandle).Msg("call setGameSettings")
+	}(time.Now())
+	return m.next.SetGameSettings(ctx, token, settings)
+}
+
+func (m loggerServerSettings) GetGameSettings(ctx context.Context, token string) (settings types.GameSettings, err error) {
+	logger := log.Ctx(ctx).With().Str("service", "ServerSettings").Str("method", "getGameSettings").Logger()
+	defer func(begin time.Time) {
+		logHandle := func(ev *zerolog.Event) {
+			fields := map[string]interface{}{
+				"request":  viewer.Sprintf("%+v", requestServerSettingsGetGameSettings{Token: token}),
+				"response": viewer.Sprintf("%+v", responseServerSettingsGetGameSettings{Settings: settings}),
+			}
+			ev.Fields(fields).Str("took", time.Since(begin).String())
+		}
+		if err != nil {
+			logger.Error().Err(err).Func(logHandle).Msg("call getGameSettings")
+			return
+		}
+		logger.Info().Func(logHandle).Msg("call getGameSettings")
+	}(time.Now())
+	return m.next.GetGameSettings(ctx, token)
+}
+
+func (m loggerServerSettings) JoinGroup(ctx context.Context, token string, name string) (err error) {
+	logger := log.Ctx(ctx).With().Str("service", "ServerSettings").Str("method", "joinGroup").Logger()
+	defer func(begin time.Time) {
+		logHandle := func(ev *zerolog.Event) {
+			fields := map[string]interface{}{
+				"request": viewer.Sprintf("%+v", requestServerSettingsJoinGroup{
+					Name:  name,
+					Token: token,
+				}),
+				"response": viewer.Sprintf("%+v", responseServerSettingsJoinGroup{}),
+			}
+			ev.Fields(fields).Str("took", time.Since(begin).String())
+		}
+		if err != nil {
+			logger.Error().Err(err).Func(logHandle).Msg("call joinGroup")
+			return
+		}
+		logger.Info().Func(logHandle).Msg("call joinGroup")
+	}(time.Now())
+	return m.next.JoinGroup(ctx, token, name)
+}
+
+func (m loggerServerSettings) LeaveGroup(ctx context.Context, token string, name string) (err error) {
+	logger := log.Ctx(ctx).With().Str("service", "ServerSettings").Str("method", "leaveGroup").Logger()
+	defer func(begin time.Time) {
+		logHandle := func(ev *zerolog.Event) {
+			fields := map[string]interface{}{
+				"request": viewer.Sprintf("%+v", requestServerSettingsLeaveGroup{
+					Name:  name,
+					Token: token,
+				}),
+				"response": viewer.Sprintf("%+v", responseServerSettingsLeaveGroup{}),
+			}
+			ev.Fields(fields).Str("took", time.Since(begin).String())
+		}
+		if err != nil {
+			logger.Error().Err(err).Func(logHandle).Msg("call leaveGroup")
+			return
+		}
+		logger.Info().Func(logHandle).Msg("call leaveGroup")
+	}(time.Now())
+	return m.next.LeaveGroup(ctx, token, name)
+}
+
+func (m loggerServerSettings) GetGroups(ctx context.Context, token string) (groups []types.Group, err error) {
+	logger := log.Ctx(ctx).With().Str("service", "ServerSettings").Str("method", "getGroups").Logger()
+	defer func(begin time.Time) {
+		logHandle := func(ev *zerolog.Event) {
+			fields := map[string]interface{}{
+				"request":  viewer.Sprintf("%+v", requestServerSettingsGetGroups{Token: token}),
+				"response": viewer.Sprintf("%+v", responseServerSettingsGetGroups{Groups: groups}),
+			}
+			ev.Fields(fields).Str("took", time.Since(begin).String())
+		}
+		if err != nil {
+			logger.Error().Err(err).Func(logHandle).Msg("call getGroups")
+			return
+		}
+		logger.Info().Func(logHandle).Msg("call getGroups")
+	}(time.Now())
+	return m.next.GetGroups(ctx, token)
+}
+
+func (m loggerServerSettings) ListRooms(ctx context.Context, token string, listRooms types.ListRoomsRequest) (rooms types.RoomList, err error) {
+	logger := log.Ctx(ctx).With().Str("service", "ServerSettings").Str("method", "listRooms").Logger()
+	defer func(begin time.Time) {
+		logHandle := func(ev *zerolog.Event) {
+			fields := map[string]interface{}{
+				"request": viewer.Sprintf("%+v", requestServerSettingsListRooms{
+					ListRooms: listRooms,
+					Token:     token,
+				}),
+				"response": viewer.Sprintf("%+v", responseServerSettingsListRooms{Rooms: rooms}),
+			}
+			ev.Fields(fields).Str("took", time.Since(begin).String())
+		}
+		if err != nil {
+			logger.Error().Err(err).Func(logHandle).Msg("call listRooms")
+			return
+		}
+		logger.Info().Func(logHandle).Msg("call listRooms")
+	}(time.Now())
+	return m.next.ListRooms(ctx, token, listRooms)
+}
+
+func (m loggerServerSettings) GetRoom(ctx context.Context, token string, room types.RoomKey) (roomInfo types.RoomInfo, err error) {
+	logger := log.Ctx(ctx).With().Str("service", "ServerSettings").Str("method", "getRoom").Logger()
+	defer func(begin time.Time) {
+		logHandle := func(ev *zerolog.Event) {
+			fields := map[string]interface{}{
+				"request": viewer.Sprintf("%+v", requestServerSettingsGetRoom{
+					Room:  room,
+					Token: token,
+				}),
+				"response": viewer.Sprintf("%+v", responseServerSettingsGetRoom{RoomInfo: roomInfo}),
+			}
+			ev.Fields(fields).Str("took", time.Since(begin).String())
+		}
+		if err != nil {
+			logger.Error().Err(err).Func(logHandle).Msg("call getRoom")
+			return
+		}
+		logger.Info().Func(logHandle).Msg("call getRoom")
+	}(time.Now())
+	return m.next.GetRoom(ctx, token, room)
+}
+
+func (m loggerServerSettings) CloseRoom(ctx context.Context, token string, closeRoom types.CloseRoomRequest) (err error) {
+	logger := log.Ctx(ctx).With().Str("service", "ServerSettings").Str("method", "closeRoom").Logger()
+	defer func(begin time.Time) {
+		logHandle := func(ev *zerolog.Event) {
+			fields := map[string]interface{}{
+				"request": viewer.Sprintf("%+v", requestServerSettingsCloseRoom{
+					CloseRoom: closeRoom,
+					Token:     token,
+				}),
+				"response": viewer.Sprintf("%+v", responseServerSettingsCloseRoom{}),
+			}
+			ev.Fields(fields).Str("took", time.Since(begin).String())
+		}
+		if err != nil {
+			logger.Error().Err(err).Func(logHandle).Msg("call closeRoom")
+			return
+		}
+		logger.Info().Func(logHandle).Msg("call closeRoom")
+	}(time.Now())
+	return m.next.CloseRoom(ctx, token, closeRoom)
+}
+
+func (m loggerServerSettings) UpdateRoom(ctx context.Context, token string, updateRoom types.UpdateRoomRequest) (err error) {
+	logger := log.Ctx(ctx).With().Str("service", "ServerSettings").Str("method", "updateRoom").Logger()
+	defer func(begin time.Time) {
+		logHandle := func(ev *zerolog.Event) {
+			fields := map[string]interface{}{
+				"request": viewer.Sprintf("%+v", requestServerSettingsUpdateRoom{
+					Token:      token,
+					UpdateRoom: updateRoom,
+				}),
+				"response": viewer.Sprintf("%+v", responseServerSettingsUpdateRoom{}),
+			}
+			ev.Fields(fields).Str("took", time.Since(begin).String())
+		}
+		if err != nil {
+			logger.Error().Err(err).Func(logHandle).Msg("call updateRoom")
+			return
+		}
+		logger.Info().Func(logHandle).Msg("call updateRoom")
+	}(time.Now())
+	return m.next.UpdateRoom(ctx, token, updateRoom)
+}
+
+func (m loggerServerSettings) KickUser(ctx context.Context, token string, kickUser types.KickUserRequest) (err error) {
+	logger := log.Ctx(ctx).With().Str("service", "ServerSettings").Str("method", "kickUser").Logger()
+	defer func(begin time.Time) {
+		logHandle := func(ev *zerolog.Event) {
+			fields := map[string]interface{}{
+				"request": viewer.Sprintf("%+v", requestServerSettingsKickUser{
+					KickUser: kickUser,
+					Token:    token,
+				}),
+				"response": viewer.Sprintf("%+v", responseServerSettingsKickUser{}),
+			}
+			ev.Fields(fields).Str("took", time.Since(begin).String())
+		}
+		if err != nil {
+			logger.Error().Err(err).Func(logHandle).Msg("call kickUser")
+			return
+		}
+		logger.Info().Func(logHandle).Msg("call kickUser")
+	}(time.Now())
+	return m.next.KickUser(ctx, token, kickUser)
+}
+
+func (m loggerServerSettings) BanUser(ctx context.Context, token string, banUser types.BanUserRequest) (err error) {
+	logger := log.Ctx(ctx).With().Str("service", "ServerSettings").Str("method", "banUser").Logger()
+	defer func(begin time.Time) {
+		logHandle := func(ev *zerolog.Event) {
+			fields := map[string]interface{}{
+				"request": viewer.Sprintf("%+v", requestServerSettingsBanUser{
+					BanUser: banUser,
+					Token:   token,
+				}),
+				"response": viewer.Sprintf("%+v", responseServerSettingsBanUser{}),
+			}
+			ev.Fields(fields).Str("took", time.Since(begin).String())
+		}
+		if err != nil {
+			logger.Error().Err(err).Func(logHandle).Msg("call banUser")
+			return
+		}
+		logger.Info().Func(logHandle).Msg("call banUser")
+	}(time.Now())
+	return m.next.BanUser(ctx, token, banUser)
+}
+
+func (m loggerServerSettings) GetRoomBans(ctx context.Context, token string, room types.RoomKey) (bans []types.Ban, err error) {
+	logger := log.Ctx(ctx).With().Str("service", "ServerSettings").Str("method", "getRoomBans").Logger()
+	defer func(begin time.Time) {
+		logHandle := func(ev *zerolog.Event) {
+			fields := map[string]interface{}{
+				"request": viewer.Sprintf("%+v", requestServerSettingsGetRoomBans{
+					Room:  room,
+					Token: token,
+				}),
+				"response": viewer.Sprintf("%+v", responseServerSettingsGetRoomBans{Bans: bans}),
+			}
+			ev.Fields(fields).Str("took", time.Since(begin).String())
+		}
+		if err != nil {
+			logger.Error().Err(err).Func(logHandle).Msg("call getRoomBans")
+			return
+		}
+		logger.Info().Func(logHandle).Msg("call getRoomBans")
+	}(time.Now())
+	return m.next.GetRoomBans(ctx, token, room)
+}
+
+func (m loggerServerSettings) GetGameBans(ctx context.Context, token string) (bans []types.Ban, err error) {
+	logger := log.Ctx(ctx).With().Str("service", "ServerSettings").Str("method", "getGameBans").Logger()
+	defer func(begin time.Time) {
+		logHandle := func(ev *zerolog.Event) {
+			fields := map[string]interface{}{
+				"request":  viewer.Sprintf("%+v", requestServerSettingsGetGameBans{Token: token}),
+				"response": viewer.Sprintf("%+v", responseServerSettingsGetGameBans{Bans: bans}),
+			}
+			ev.Fields(fields).Str("took", time.Since(begin).String())
+		}
+		if err != nil {
+			logger.Error().Err(err).Func(logHandle).Msg("call getGameBans")
+			return
+		}
+		logger.Info().Func(logHandle).Msg("call getGameBans")
+	}(time.Now())
+	return m.next.GetGameBans(ctx, token)
+}
+
+func (m loggerServerSettings) SetRoomMetadata(ctx context.Context, token string, setRoomMetadata types.SetRoomMetadataRequest) (version uint64, err error) {
+	logger := log.Ctx(ctx).With().Str("service", "ServerSettings").Str("method", "setRoomMetadata").Logger()
+	defer func(begin time.Time) {
+		logHandle := func(ev *zerolog.Event) {
+			fields := map[string]interface{}{
+				"request": viewer.Sprintf("%+v", requestServerSettingsSetRoomMetadata{
+					SetRoomMetadata: setRoomMetadata,
+					Token:           token,
+				}),
+				"response": viewer.Sprintf("%+v", responseServerSettingsSetRoomMetadata{Version: version}),
+			}
+			ev.Fields(fields).Str("took", time.Since(begin).String())
+		}
+		if err != nil {
+			logger.Error().Err(err).Func(logHandle).Msg("call setRoomMetadata")
+			return
+		}
+		logger.Info().Func(logHandle).Msg("call setRoomMetadata")
+	}(time.Now())
+	return m.next.SetRoomMetadata(ctx, token, setRoomMetadata)
+}
@@ -12,6 +12,22 @@ type ServerSettingsHealthCheck func(ctx context.Context, token string) (exists b
 type ServerSettingsGetServerSettings func(ctx context.Context, token string) (settings types.Settings, err error)
 type ServerSettingsCreateRoom func(ctx context.Context, token string, createRoom types.CreateRoomRequest) (err error)
 type ServerSettingsGetDeletedRooms func(ctx context.Context, token string, ids []types.GetDeletedRooms) (deletedIds []types.GetDeletedRooms, err error)
+type ServerSettingsGetRoomStats func(ctx context.Context, token string) (stats types.RoomStats, err error)
+type ServerSettingsGetLinkQuality func(ctx context.Context, token string) (quality []types.LinkQuality, err error)
+type ServerSettingsSetGameSettings func(ctx context.Context, token string, settings types.GameSettings) (err error)
+type ServerSettingsGetGameSettings func(ctx context.Context, token string) (settings types.GameSettings, err error)
+type ServerSettingsJoinGroup func(ctx context.Context, token string, name string) (err error)
+type ServerSettingsLeaveGroup func(ctx context.Context, token string, name string) (err error)
+type ServerSettingsGetGroups func(ctx context.Context, token string) (groups []types.Group, err error)
+type ServerSettingsListRooms func(ctx context.Context, token string, listRooms types.ListRoomsRequest) (rooms types.RoomList, err error)
+type ServerSettingsGetRoom func(ctx context.Context, token string, room types.RoomKey) (roomInfo types.RoomInfo, err error)
+type ServerSettingsCloseRoom func(ctx context.Context, token string, closeRoom types.CloseRoomRequest) (err error)
+type ServerSettingsUpdateRoom func(ctx context.Context, token string, updateRoom types.UpdateRoomRequest) (err error)
+type ServerSettingsKickUser func(ctx context.Context, token string, kickUser types.KickUserRequest) (err error)
+type ServerSettingsBanUser func(ctx context.Context, token string, banUser types.BanUserRequest) (err error)
+type ServerSettingsGetRoomBans func(ctx context.Context, token string, room types.RoomKey) (bans []types.Ban, err error)
+type ServerSettingsGetGameBans func(ctx context.Context, token string) (bans []types.Ban, err error)
+type ServerSettingsSetRoomMetadata func(ctx context.Context, token string, setRoomMetadata types.SetRoomMetadataRequest) (version uint64, err error)
 
 type MiddlewareServerSettings func(next api.ServerSettings) api.ServerSettings
 
@@ -20,3 +36,19 @@ type MiddlewareServerSettingsHealthCheck func(next ServerSettingsHealthCheck) Se
 type MiddlewareServerSettingsGetServerSettings func(next ServerSettingsGetServerSettings) ServerSettingsGetServerSettings
 type MiddlewareServerSettingsCreateRoom func(next ServerSettingsCreateRoom) ServerSettingsCreateRoom
 type MiddlewareServerSettingsGetDeletedRooms func(next ServerSettingsGetDeletedRooms) ServerSettingsGetDeletedRooms
+type MiddlewareServerSettingsGetRoomStats func(next ServerSettingsGetRoomStats) ServerSettingsGetRoomStats
+type MiddlewareServerSettingsGetLinkQuality func(next ServerSettingsGetLinkQuality) ServerSettingsGetLinkQuality
+type MiddlewareServerSettingsSetGameSettings func(next ServerSettingsSetGameSettings) ServerSettingsSetGameSettings
+type MiddlewareServerSettingsGetGameSettings func(next ServerSettingsGetGameSettings) ServerSettingsGetGameSettings
+type MiddlewareServerSettingsJoinGroup func(next ServerSettingsJoinGroup) ServerSettingsJoinGroup
+type MiddlewareServerSettingsLeaveGroup func(next ServerSettingsLeaveGroup) ServerSettingsLeaveGroup
+type MiddlewareServerSettingsGetGroups func(next ServerSettingsGetGroups) ServerSettingsGetGroups
+type MiddlewareServerSettingsListRooms func(next ServerSettingsListRooms) ServerSettingsListRooms
+type MiddlewareServerSettingsGetRoom func(next ServerSettingsGetRoom) ServerSettingsGetRoom
+type MiddlewareServerSettingsCloseRoom func(next ServerSettingsCloseRoom) ServerSettingsCloseRoom
+type MiddlewareServerSettingsUpdateRoom func(next ServerSettingsUpdateRoom) ServerSettingsUpdateRoom
+type MiddlewareServerSettingsKickUser func(next ServerSettingsKickUser) ServerSettingsKickUser
+type MiddlewareServerSettingsBanUser func(next ServerSettingsBanUser) ServerSettingsBanUser
+type MiddlewareServerSettingsGetRoomBans func(next ServerSettingsGetRoomBans) ServerSettingsGetRoomBans
+type MiddlewareServerSettingsGetGameBans func(next ServerSettingsGetGameBans) ServerSettingsGetGameBans
+type MiddlewareServerSettingsSetRoomMetadata func(next ServerSettingsSetRoomMetadata) ServerSettingsSetRoomMetadata
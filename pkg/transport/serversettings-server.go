@@ -14,6 +14,22 @@ type serverServerSettings struct {
 	getServerSettings ServerSettingsGetServerSettings
 	createRoom        ServerSettingsCreateRoom
 	getDeletedRooms   ServerSettingsGetDeletedRooms
+	getRoomStats      ServerSettingsGetRoomStats
+	getLinkQuality    ServerSettingsGetLinkQuality
+	setGameSettings   ServerSettingsSetGameSettings
+	getGameSettings   ServerSettingsGetGameSettings
+	joinGroup         ServerSettingsJoinGroup
+	leaveGroup        ServerSettingsLeaveGroup
+	getGroups         ServerSettingsGetGroups
+	listRooms         ServerSettingsListRooms
+	getRoom           ServerSettingsGetRoom
+	closeRoom         ServerSettingsCloseRoom
+	updateRoom        ServerSettingsUpdateRoom
+	kickUser          ServerSettingsKickUser
+	banUser           ServerSettingsBanUser
+	getRoomBans       ServerSettingsGetRoomBans
+	getGameBans       ServerSettingsGetGameBans
+	setRoomMetadata   ServerSettingsSetRoomMetadata
 }
 
 type MiddlewareSetServerSettings interface {
@@ -23,6 +39,22 @@ type MiddlewareSetServerSettings interface {
 	WrapGetServerSettings(m MiddlewareServerSettingsGetServerSettings)
 	WrapCreateRoom(m MiddlewareServerSettingsCreateRoom)
 	WrapGetDeletedRooms(m MiddlewareServerSettingsGetDeletedRooms)
+	WrapGetRoomStats(m MiddlewareServerSettingsGetRoomStats)
+	WrapGetLinkQuality(m MiddlewareServerSettingsGetLinkQuality)
+	WrapSetGameSettings(m MiddlewareServerSettingsSetGameSettings)
+	WrapGetGameSettings(m MiddlewareServerSettingsGetGameSettings)
+	WrapJoinGroup(m MiddlewareServerSettingsJoinGroup)
+	WrapLeaveGroup(m MiddlewareServerSettingsLeaveGroup)
+	WrapGetGroups(m MiddlewareServerSettingsGetGroups)
+	WrapListRooms(m MiddlewareServerSettingsListRooms)
+	WrapGetRoom(m MiddlewareServerSettingsGetRoom)
+	WrapCloseRoom(m MiddlewareServerSettingsCloseRoom)
+	WrapUpdateRoom(m MiddlewareServerSettingsUpdateRoom)
+	WrapKickUser(m MiddlewareServerSettingsKickUser)
+	WrapBanUser(m MiddlewareServerSettingsBanUser)
+	WrapGetRoomBans(m MiddlewareServerSettingsGetRoomBans)
+	WrapGetGameBans(m MiddlewareServerSettingsGetGameBans)
+	WrapSetRoomMetadata(m MiddlewareServerSettingsSetRoomMetadata)
 
 	WithTrace()
 	WithLog()
@@ -30,12 +62,28 @@ type MiddlewareSetServerSettings interface {
 
 func newServerServerSettings(svc api.ServerSettings) *serverServerSettings {
 	return &serverServerSettings{
+		banUser:           svc.BanUser,
+		closeRoom:         svc.CloseRoom,
 		createRoom:        svc.CreateRoom,
 		getConnectionsNum: svc.GetConnectionsNum,
 		getDeletedRooms:   svc.GetDeletedRooms,
+		getGameBans:       svc.GetGameBans,
+		getGameSettings:   svc.GetGameSettings,
+		getGroups:         svc.GetGroups,
+		getLinkQuality:    svc.GetLinkQuality,
+		getRoom:           svc.GetRoom,
+		getRoomBans:       svc.GetRoomBans,
+		getRoomStats:      svc.GetRoomStats,
 		getServerSettings: svc.GetServerSettings,
 		healthCheck:       svc.HealthCheck,
+		joinGroup:         svc.JoinGroup,
+		kickUser:          svc.KickUser,
+		leaveGroup:        svc.LeaveGroup,
+		listRooms:         svc.ListRooms,
+		setGameSettings:   svc.SetGameSettings,
+		setRoomMetadata:   svc.SetRoomMetadata,
 		svc:               svc,
+		updateRoom:        svc.UpdateRoom,
 	}
 }
 
@@ -46,6 +94,22 @@ func (srv *serverServerSettings) Wrap(m MiddlewareServerSettings) {
 	srv.getServerSettings = srv.svc.GetServerSettings
 	srv.createRoom = srv.svc.CreateRoom
 	srv.getDeletedRooms = srv.svc.GetDeletedRooms
+	srv.getRoomStats = srv.svc.GetRoomStats
+	srv.getLinkQuality = srv.svc.GetLinkQuality
+	srv.setGameSettings = srv.svc.SetGameSettings
+	srv.getGameSettings = srv.svc.GetGameSettings
+	srv.joinGroup = srv.svc.JoinGroup
+	srv.leaveGroup = srv.svc.LeaveGroup
+	srv.getGroups = srv.svc.GetGroups
+	srv.listRooms = srv.svc.ListRooms
+	srv.getRoom = srv.svc.GetRoom
+	srv.closeRoom = srv.svc.CloseRoom
+	srv.updateRoom = srv.svc.UpdateRoom
+	srv.kickUser = srv.svc.KickUser
+	srv.banUser = srv.svc.BanUser
+	srv.getRoomBans = srv.svc.GetRoomBans
+	srv.getGameBans = srv.svc.GetGameBans
+	srv.setRoomMetadata = srv.svc.SetRoomMetadata
 }
 
 func (srv *serverServerSettings) GetConnectionsNum(ctx context.Context, token string) (countConn int, exists bool, err error) {
@@ -68,6 +132,70 @@ func (srv *serverServerSettings) GetDeletedRooms(ctx context.Context, token stri
 	return srv.getDeletedRooms(ctx, token, ids)
 }
 
+func (srv *serverServerSettings) GetRoomStats(ctx context.Context, token string) (stats types.RoomStats, err error) {
+	return srv.getRoomStats(ctx, token)
+}
+
+func (srv *serverServerSettings) GetLinkQuality(ctx context.Context, token string) (quality []types.LinkQuality, err error) {
+	return srv.getLinkQuality(ctx, token)
+}
+
+func (srv *serverServerSettings) SetGameSettings(ctx context.Context, token string, settings types.GameSettings) (err error) {
+	return srv.setGameSettings(ctx, token, settings)
+}
+
+func (srv *serverServerSettings) GetGameSettings(ctx context.Context, token string) (settings types.GameSettings, err error) {
+	return srv.getGameSettings(ctx, token)
+}
+
+func (srv *serverServerSettings) JoinGroup(ctx context.Context, token string, name string) (err error) {
+	return srv.joinGroup(ctx, token, name)
+}
+
+func (srv *serverServerSettings) LeaveGroup(ctx context.Context, token string, name string) (err error) {
+	return srv.leaveGroup(ctx, token, name)
+}
+
+func (srv *serverServerSettings) GetGroups(ctx context.Context, token string) (groups []types.Group, err error) {
+	return srv.getGroups(ctx, token)
+}
+
+func (srv *serverServerSettings) ListRooms(ctx context.Context, token string, listRooms types.ListRoomsRequest) (rooms types.RoomList, err error) {
+	return srv.listRooms(ctx, token, listRooms)
+}
+
+func (srv *serverServerSettings) GetRoom(ctx context.Context, token string, room types.RoomKey) (roomInfo types.RoomInfo, err error) {
+	return srv.getRoom(ctx, token, room)
+}
+
+func (srv *serverServerSettings) CloseRoom(ctx context.Context, token string, closeRoom types.CloseRoomRequest) (err error) {
+	return srv.closeRoom(ctx, token, closeRoom)
+}
+
+func (srv *serverServerSettings) UpdateRoom(ctx context.Context, token string, updateRoom types.UpdateRoomRequest) (err error) {
+	return srv.updateRoom(ctx, token, updateRoom)
+}
+
+func (srv *serverServerSettings) KickUser(ctx context.Context, token string, kickUser types.KickUserRequest) (err error) {
+	return srv.kickUser(ctx, token, kickUser)
+}
+
+func (srv *serverServerSettings) BanUser(ctx context.Context, token string, banUser types.BanUserRequest) (err error) {
+	return srv.banUser(ctx, token, banUser)
+}
+
+func (srv *serverServerSettings) GetRoomBans(ctx context.Context, token string, room types.RoomKey) (bans []types.Ban, err error) {
+	return srv.getRoomBans(ctx, token, room)
+}
+
+func (srv *serverServerSettings) GetGameBans(ctx context.Context, token string) (bans []types.Ban, err error) {
+	return srv.getGameBans(ctx, token)
+}
+
+func (srv *serverServerSettings) SetRoomMetadata(ctx context.Context, token string, setRoomMetadata types.SetRoomMetadataRequest) (version uint64, err error) {
+	return srv.setRoomMetadata(ctx, token, setRoomMetadata)
+}
+
 func (srv *serverServerSettings) WrapGetConnectionsNum(m MiddlewareServerSettingsGetConnectionsNum) {
 	srv.getConnectionsNum = m(srv.getConnectionsNum)
 }
@@ -88,6 +216,70 @@ func (srv *serverServerSettings) WrapGetDeletedRooms(m MiddlewareServerSettingsG
 	srv.getDeletedRooms = m(srv.getDeletedRooms)
 }
 
+func (srv *serverServerSettings) WrapGetRoomStats(m MiddlewareServerSettingsGetRoomStats) {
+	srv.getRoomStats = m(srv.getRoomStats)
+}
+
+func (srv *serverServerSettings) WrapGetLinkQuality(m MiddlewareServerSettingsGetLinkQuality) {
+	srv.getLinkQuality = m(srv.getLinkQuality)
+}
+
+func (srv *serverServerSettings) WrapSetGameSettings(m MiddlewareServerSettingsSetGameSettings) {
+	srv.setGameSettings = m(srv.setGameSettings)
+}
+
+func (srv *serverServerSettings) WrapGetGameSettings(m MiddlewareServerSettingsGetGameSettings) {
+	srv.getGameSettings = m(srv.getGameSettings)
+}
+
+func (srv *serverServerSettings) WrapJoinGroup(m MiddlewareServerSettingsJoinGroup) {
+	srv.joinGroup = m(srv.joinGroup)
+}
+
+func (srv *serverServerSettings) WrapLeaveGroup(m MiddlewareServerSettingsLeaveGroup) {
+	srv.leaveGroup = m(srv.leaveGroup)
+}
+
+func (srv *serverServerSettings) WrapGetGroups(m MiddlewareServerSettingsGetGroups) {
+	srv.getGroups = m(srv.getGroups)
+}
+
+func (srv *serverServerSettings) WrapListRooms(m MiddlewareServerSettingsListRooms) {
+	srv.listRooms = m(srv.listRooms)
+}
+
+func (srv *serverServerSettings) WrapGetRoom(m MiddlewareServerSettingsGetRoom) {
+	srv.getRoom = m(srv.getRoom)
+}
+
+func (srv *serverServerSettings) WrapCloseRoom(m MiddlewareServerSettingsCloseRoom) {
+	srv.closeRoom = m(srv.closeRoom)
+}
+
+func (srv *serverServerSettings) WrapUpdateRoom(m MiddlewareServerSettingsUpdateRoom) {
+	srv.updateRoom = m(srv.updateRoom)
+}
+
+func (srv *serverServerSettings) WrapKickUser(m MiddlewareServerSettingsKickUser) {
+	srv.kickUser = m(srv.kickUser)
+}
+
+func (srv *serverServerSettings) WrapBanUser(m MiddlewareServerSettingsBanUser) {
+	srv.banUser = m(srv.banUser)
+}
+
+func (srv *serverServerSettings) WrapGetRoomBans(m MiddlewareServerSettingsGetRoomBans) {
+	srv.getRoomBans = m(srv.getRoomBans)
+}
+
+func (srv *serverServerSettings) WrapGetGameBans(m MiddlewareServerSettingsGetGameBans) {
+	srv.getGameBans = m(srv.getGameBans)
+}
+
+func (srv *serverServerSettings) WrapSetRoomMetadata(m MiddlewareServerSettingsSetRoomMetadata) {
+	srv.setRoomMetadata = m(srv.setRoomMetadata)
+}
+
 func (srv *serverServerSettings) WithTrace() {
 	srv.Wrap(traceMiddlewareServerSettings)
 }
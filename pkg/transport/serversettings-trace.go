@@ -45,3 +45,99 @@ func (svc traceServerSettings) GetDeletedRooms(ctx context.Context, token string
 	span.SetTag("method", "GetDeletedRooms")
 	return svc.next.GetDeletedRooms(ctx, token, ids)
 }
+
+func (svc traceServerSettings) GetRoomStats(ctx context.Context, token string) (stats types.RoomStats, err error) {
+	span := opentracing.SpanFromContext(ctx)
+	span.SetTag("method", "GetRoomStats")
+	return svc.next.GetRoomStats(ctx, token)
+}
+
+func (svc traceServerSettings) GetLinkQuality(ctx context.Context, token string) (quality []types.LinkQuality, err error) {
+	span := opentracing.SpanFromContext(ctx)
+	span.SetTag("method", "GetLinkQuality")
+	return svc.next.GetLinkQuality(ctx, token)
+}
+
+func (svc traceServerSettings) SetGameSettings(ctx context.Context, token string, settings types.GameSettings) (err error) {
+	span := opentracing.SpanFromContext(ctx)
+	span.SetTag("method", "SetGameSettings")
+	return svc.next.SetGameSettings(ctx, token, settings)
+}
+
+func (svc traceServerSettings) GetGameSettings(ctx context.Context, token string) (settings types.GameSettings, err error) {
+	span := opentracing.SpanFromContext(ctx)
+	span.SetTag("method", "GetGameSettings")
+	return svc.next.GetGameSettings(ctx, token)
+}
+
+func (svc traceServerSettings) JoinGroup(ctx context.Context, token string, name string) (err error) {
+	span := opentracing.SpanFromContext(ctx)
+	span.SetTag("method", "JoinGroup")
+	return svc.next.JoinGroup(ctx, token, name)
+}
+
+func (svc traceServerSettings) LeaveGroup(ctx context.Context, token string, name string) (err error) {
+	span := opentracing.SpanFromContext(ctx)
+	span.SetTag("method", "LeaveGroup")
+	return svc.next.LeaveGroup(ctx, token, name)
+}
+
+func (svc traceServerSettings) GetGroups(ctx context.Context, token string) (groups []types.Group, err error) {
+	span := opentracing.SpanFromContext(ctx)
+	span.SetTag("method", "GetGroups")
+	return svc.next.GetGroups(ctx, token)
+}
+
+func (svc traceServerSettings) ListRooms(ctx context.Context, token string, listRooms types.ListRoomsRequest) (rooms types.RoomList, err error) {
+	span := opentracing.SpanFromContext(ctx)
+	span.SetTag("method", "ListRooms")
+	return svc.next.ListRooms(ctx, token, listRooms)
+}
+
+func (svc traceServerSettings) GetRoom(ctx context.Context, token string, room types.RoomKey) (roomInfo types.RoomInfo, err error) {
+	span := opentracing.SpanFromContext(ctx)
+	span.SetTag("method", "GetRoom")
+	return svc.next.GetRoom(ctx, token, room)
+}
+
+func (svc traceServerSettings) CloseRoom(ctx context.Context, token string, closeRoom types.CloseRoomRequest) (err error) {
+	span := opentracing.SpanFromContext(ctx)
+	span.SetTag("method", "CloseRoom")
+	return svc.next.CloseRoom(ctx, token, closeRoom)
+}
+
+func (svc traceServerSettings) UpdateRoom(ctx context.Context, token string, updateRoom types.UpdateRoomRequest) (err error) {
+	span := opentracing.SpanFromContext(ctx)
+	span.SetTag("method", "UpdateRoom")
+	return svc.next.UpdateRoom(ctx, token, updateRoom)
+}
+
+func (svc traceServerSettings) KickUser(ctx context.Context, token string, kickUser types.KickUserRequest) (err error) {
+	span := opentracing.SpanFromContext(ctx)
+	span.SetTag("method", "KickUser")
+	return svc.next.KickUser(ctx, token, kickUser)
+}
+
+func (svc traceServerSettings) BanUser(ctx context.Context, token string, banUser types.BanUserRequest) (err error) {
+	span := opentracing.SpanFromContext(ctx)
+	span.SetTag("method", "BanUser")
+	return svc.next.BanUser(ctx, token, banUser)
+}
+
+func (svc traceServerSettings) GetRoomBans(ctx context.Context, token string, room types.RoomKey) (bans []types.Ban, err error) {
+	span := opentracing.SpanFromContext(ctx)
+	span.SetTag("method", "GetRoomBans")
+	return svc.next.GetRoomBans(ctx, token, room)
+}
+
+func (svc traceServerSettings) GetGameBans(ctx context.Context, token string) (bans []types.Ban, err error) {
+	span := opentracing.SpanFromContext(ctx)
+	span.SetTag("method", "GetGameBans")
+	return svc.next.GetGameBans(ctx, token)
+}
+
+func (svc traceServerSettings) SetRoomMetadata(ctx context.Context, token string, setRoomMetadata types.SetRoomMetadataRequest) (version uint64, err error) {
+	span := opentracing.SpanFromContext(ctx)
+	span.SetTag("method", "SetRoomMetadata")
+	return svc.next.SetRoomMetadata(ctx, token, setRoomMetadata)
+}